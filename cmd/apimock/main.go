@@ -1,51 +1,40 @@
 package main
 
 import (
-	"encoding/json"
+	"flag"
 	"log"
 	"net/http"
-	"sync/atomic"
+	"strings"
+
+	"sea-qa/internal/contract"
+	"sea-qa/internal/mock"
 )
 
+// apimock serves the demo spec (openapi-dev.yaml) for local runs of suite.yaml.
+// It is equivalent to `seaqa mock --openapi openapi-dev.yaml --addr :8081 --stateful`.
 func main() {
-	var cleanupCount int32
-
-	mux := http.NewServeMux()
+	specPath := flag.String("openapi", "openapi-dev.yaml", "OpenAPI document to mock")
+	addr := flag.String("addr", ":8081", "Listen address")
+	fixtures := flag.String("fixtures", "", "Comma-separated fixture files/dirs seeding the store")
+	flag.Parse()
 
-	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.NotFound(w, r)
-			return
-		}
-		var in struct {
-			Email string `json:"email"`
-			Name  string `json:"name"`
+	v, err := contract.LoadFromFile(*specPath)
+	if err != nil {
+		log.Fatalf("openapi load: %v", err)
+	}
+	var fx mock.Fixtures
+	if *fixtures != "" {
+		if fx, err = mock.LoadFixtures(strings.Split(*fixtures, ",")); err != nil {
+			log.Fatal(err)
 		}
-		_ = json.NewDecoder(r.Body).Decode(&in)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusCreated)
-		_ = json.NewEncoder(w).Encode(map[string]any{
-			"id":    "u-123",
-			"email": in.Email,
-			"name":  in.Name,
-		})
-	})
-
-	mux.HandleFunc("/fail", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`{"ok":true}`))
+	}
+	srv, err := mock.New(v, mock.Options{
+		Validate: true, CORS: true, Log: log.Default(),
+		Store: mock.NewStore(fx),
 	})
-
-	mux.HandleFunc("/cleanup", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.NotFound(w, r)
-			return
-		}
-		atomic.AddInt32(&cleanupCount, 1)
-		w.WriteHeader(http.StatusNoContent)
-	})
-
-	addr := ":8081"
-	log.Printf("apimock listening on %s", addr)
-	log.Fatal(http.ListenAndServe(addr, mux))
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("apimock listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, srv))
 }
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"sea-qa/internal/contract"
+	"sea-qa/internal/curl"
+	"sea-qa/internal/executor"
+	"sea-qa/internal/har"
+	"sea-qa/internal/ir"
+	"sea-qa/internal/reporter"
+)
+
+type artifactOptions struct {
+	outDir string
+	name   string
+	json   bool
+	junit  bool
+	html   bool
+	har    bool
+}
+
+// writeResults writes results.json, junit.xml, report.html and run.har as enabled.
+func writeResults(o artifactOptions, res *executor.SuiteResult) {
+	if err := os.MkdirAll(o.outDir, 0o755); err != nil {
+		fail("mkdir out: %v", err)
+	}
+
+	// Always compute suite name for outputs
+	outSuiteName := o.name
+	if outSuiteName == "" {
+		outSuiteName = "sea-qa"
+	}
+
+	// JSON (and remember the path for HTML parity)
+	var jsonPath string
+	if o.json {
+		jsonPath = filepath.Join(o.outDir, "results.json")
+		writeOrDie(jsonPath, func(f *os.File) error {
+			return reporter.WriteJSON(f, res)
+		})
+	}
+
+	// JUnit
+	if o.junit {
+		writeOrDie(filepath.Join(o.outDir, "junit.xml"), func(f *os.File) error {
+			return reporter.WriteJUnit(f, outSuiteName, res)
+		})
+	}
+
+	// HTML — if JSON is enabled, render from results.json to guarantee parity
+	if o.html {
+		htmlPath := filepath.Join(o.outDir, "report.html")
+		if jsonPath != "" {
+			writeOrDie(htmlPath, func(f *os.File) error {
+				return reporter.WriteHTMLFromJSONPath(f, outSuiteName, jsonPath)
+			})
+		} else {
+			// fallback: render directly from memory
+			writeOrDie(htmlPath, func(f *os.File) error {
+				return reporter.WriteHTML(f, outSuiteName, res)
+			})
+		}
+	}
+
+	if o.har {
+		writeOrDie(filepath.Join(o.outDir, "run.har"), func(f *os.File) error {
+			return har.Write(f, har.FromResults(res, buildVersion()))
+		})
+	}
+}
+
+// writeDrift writes drift.json and notes undocumented response elements.
+func writeDrift(outDir string, res *executor.SuiteResult) {
+	writeOrDie(filepath.Join(outDir, "drift.json"), func(f *os.File) error {
+		return reporter.WriteDrift(f, res.Drift)
+	})
+	if rep := reporter.NewDriftReport(res.Drift); rep.Items > 0 {
+		fmt.Fprintf(os.Stderr, "spec drift: %d undocumented item(s) in %d operation(s), see %s\n",
+			rep.Items, rep.Operations, filepath.Join(outDir, "drift.json"))
+	}
+}
+
+// writeCoverage writes coverage.json and returns the (aggregate) percentage.
+// A lone default spec keeps the single-spec format; otherwise per-spec.
+func writeCoverage(outDir string, specs *contract.Registry, r *executor.Runner) float64 {
+	if specs.Len() == 1 && specs.Routes()[0].Name == "" {
+		doc := specs.Routes()[0].Validator.Doc()
+		writeOrDie(filepath.Join(outDir, "coverage.json"), func(f *os.File) error {
+			return reporter.WriteCoverage(f, doc, r.Covered())
+		})
+		return reporter.ComputeCoverage(doc, r.Covered()).Percent
+	}
+	docs := map[string]*openapi3.T{}
+	for _, rt := range specs.Routes() {
+		docs[rt.Name] = rt.Validator.Doc()
+	}
+	writeOrDie(filepath.Join(outDir, "coverage.json"), func(f *os.File) error {
+		return reporter.WriteMultiCoverage(f, docs, r.CoveredBySpec())
+	})
+	return reporter.ComputeMultiCoverage(docs, r.CoveredBySpec()).Percent
+}
+
+// buildVersion is the module version the binary was built from, or "" for
+// development builds.
+func buildVersion() string {
+	if bi, ok := debug.ReadBuildInfo(); ok && bi.Main.Version != "(devel)" {
+		return bi.Main.Version
+	}
+	return ""
+}
+
+// redactionFlags registers the --redact-* flags on fs; call the result
+// after parsing.
+func redactionFlags(fs *flag.FlagSet) func() ir.Redaction {
+	headers := fs.String("redact-headers", "", "Comma-separated header names to mask in results (* wildcards; credential headers are always masked)")
+	vars := fs.String("redact-vars", "", "Comma-separated var names whose values are masked in results (* wildcards)")
+	paths := fs.String("redact-json", "", "Comma-separated JSON paths to mask in bodies (e.g. $.password,$..token)")
+	return func() ir.Redaction {
+		return ir.Redaction{Headers: splitCSV(*headers), Vars: splitCSV(*vars), JSONPaths: splitCSV(*paths)}
+	}
+}
+
+// printFailures prints failed scenarios/steps to stderr.
+func printFailures(res *executor.SuiteResult, verbose bool) {
+	if res.Passed && !verbose {
+		return
+	}
+	if len(res.Errors) > 0 {
+		fmt.Fprintf(os.Stderr, "\nSuite hooks FAILED:\n")
+		for _, e := range res.Errors {
+			fmt.Fprintf(os.Stderr, "  - %s\n", e)
+		}
+	}
+	for _, sc := range res.Scenarios {
+		if sc.Passed {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "\nScenario FAILED: %s\n", sc.Name)
+		for _, e := range sc.Errors {
+			fmt.Fprintf(os.Stderr, "  - %s\n", e)
+		}
+		for i, st := range sc.Steps {
+			if st.Passed {
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "  Step %d: status=%d\n", i+1, st.StatusCode)
+			for _, e := range st.Errors {
+				fmt.Fprintf(os.Stderr, "    - %s\n", e)
+			}
+			for _, c := range st.Checks {
+				if !c.Passed {
+					fmt.Fprintf(os.Stderr, "    - check %s: %s\n", c.Name, reporter.CheckMessage(c))
+				}
+			}
+			if verbose && st.Method != "" && st.URL != "" {
+				cmd := curl.Command(st.Method, st.URL, st.ReqHeaders, st.ReqBody)
+				fmt.Fprintf(os.Stderr, "    reproduce:\n      %s\n", strings.ReplaceAll(cmd, "\n", "\n      "))
+			}
+		}
+	}
+}
+
+func exitWithResult(passed bool) {
+	if passed {
+		fmt.Println("PASS")
+		os.Exit(0)
+	}
+	fmt.Println("FAIL")
+	os.Exit(1)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"sea-qa/internal/contract"
+	"sea-qa/internal/executor"
+	"sea-qa/internal/generate"
+	"sea-qa/internal/vars"
+)
+
+// seaqa examples --openapi spec.yaml --env env/dev.json
+// Runs every documented request example against the API with contract checks.
+func runExamples(args []string) {
+	fs := flag.NewFlagSet("examples", flag.ExitOnError)
+	openapiPath := fs.String("openapi", "", "Path to OpenAPI (YAML/JSON) whose examples are executed")
+	envPaths := fs.String("env", "", "Comma-separated JSON env files (must define the base URL variable)")
+	baseVar := fs.String("base-var", "BASE_URL", "Variable used as URL prefix in generated requests")
+	outDir := fs.String("out", "reports", "Output directory for artifacts")
+	save := fs.String("save", "", "Also write the generated suite to this file")
+	parallel := fs.Int("parallel", 1, "Number of scenarios to execute in parallel")
+	covMin := fs.Float64("coverage-min", -1, "Fail if coverage percent < this threshold")
+	jsonOut := fs.Bool("json", true, "Write JSON results")
+	junitOut := fs.Bool("junit", true, "Write JUnit XML results")
+	htmlOut := fs.Bool("html", true, "Write HTML report")
+	verbose := fs.Bool("v", false, "Verbose: print failure details")
+	redaction := redactionFlags(fs)
+	_ = fs.Parse(args)
+
+	if *openapiPath == "" {
+		fail("examples: missing --openapi")
+	}
+	v, err := contract.LoadFromFile(*openapiPath)
+	if err != nil {
+		fail("openapi load: %v", err)
+	}
+	var baseVars map[string]string
+	if *envPaths != "" {
+		baseVars, err = vars.LoadJSONFiles(strings.Split(*envPaths, ","))
+		if err != nil {
+			fail("load env: %v", err)
+		}
+	}
+
+	suite, skipped := generate.FromExamples(v.Doc(), generate.Options{
+		OpenAPI: specRef(*openapiPath, *save),
+		BaseVar: *baseVar,
+	})
+	for _, s := range skipped {
+		fmt.Fprintf(os.Stderr, "skipped %s %s: %s\n", s.Method, s.Path, s.Reason)
+	}
+	if len(suite.Scenarios) == 0 {
+		fail("examples: no operation has usable request examples")
+	}
+	if *save != "" {
+		writeSuite(*save, suite)
+	}
+
+	specs := contract.NewRegistry()
+	specs.Add("", contract.Match{}, v)
+	r := executor.NewWithVars(baseVars).WithParallel(*parallel).WithContracts(specs).WithRedaction(redaction())
+	res, err := r.RunSuite(context.Background(), suite)
+	if err != nil {
+		fail("execute: %v", err)
+	}
+
+	writeResults(artifactOptions{
+		outDir: *outDir, name: suite.Name,
+		json: *jsonOut, junit: *junitOut, html: *htmlOut,
+	}, res)
+	percent := writeCoverage(*outDir, specs, r)
+	writeDrift(*outDir, res)
+	fmt.Printf("Examples: %d scenario(s), %d operation(s) skipped, coverage %.2f%%\n",
+		len(suite.Scenarios), len(skipped), percent)
+	if *covMin >= 0 && percent+1e-9 < *covMin {
+		fmt.Fprintf(os.Stderr, "coverage gate failed: got %.2f%%, need >= %.2f%%\n", percent, *covMin)
+		fmt.Println("FAIL")
+		os.Exit(1)
+	}
+
+	printFailures(res, *verbose)
+	exitWithResult(res.Passed)
+}
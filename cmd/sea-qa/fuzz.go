@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"sea-qa/internal/contract"
+	"sea-qa/internal/executor"
+	"sea-qa/internal/fuzz"
+	"sea-qa/internal/vars"
+)
+
+// seaqa fuzz --openapi spec.yaml --env env/dev.json [--seed N] [--max-cases N]
+func runFuzz(args []string) {
+	fs := flag.NewFlagSet("fuzz", flag.ExitOnError)
+	openapiPath := fs.String("openapi", "", "Path to OpenAPI (YAML/JSON) to derive requests from")
+	envPaths := fs.String("env", "", "Comma-separated JSON env files (must define the base URL variable)")
+	baseVar := fs.String("base-var", "BASE_URL", "Variable used as URL prefix in generated requests")
+	seed := fs.Int64("seed", 0, "Random seed (0 picks one; the seed used is printed and saved)")
+	maxCases := fs.Int("max-cases", 0, "Maximum cases per operation (0 = all)")
+	outDir := fs.String("out", "reports", "Output directory for artifacts")
+	parallel := fs.Int("parallel", 1, "Number of operations to fuzz in parallel")
+	jsonOut := fs.Bool("json", true, "Write JSON results")
+	junitOut := fs.Bool("junit", true, "Write JUnit XML results")
+	htmlOut := fs.Bool("html", true, "Write HTML report")
+	verbose := fs.Bool("v", false, "Verbose: print failure details")
+	redaction := redactionFlags(fs)
+	_ = fs.Parse(args)
+
+	if *openapiPath == "" {
+		fail("fuzz: missing --openapi")
+	}
+	v, err := contract.LoadFromFile(*openapiPath)
+	if err != nil {
+		fail("openapi load: %v", err)
+	}
+	var baseVars map[string]string
+	if *envPaths != "" {
+		baseVars, err = vars.LoadJSONFiles(strings.Split(*envPaths, ","))
+		if err != nil {
+			fail("load env: %v", err)
+		}
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	plan := fuzz.Generate(v.Doc(), fuzz.Options{Seed: *seed, MaxCases: *maxCases, BaseVar: *baseVar})
+	suite := plan.Suite("fuzz")
+
+	r := executor.NewWithVars(baseVars).WithParallel(*parallel).WithRedaction(redaction())
+	res, err := r.RunSuite(context.Background(), suite)
+	if err != nil {
+		fail("execute: %v", err)
+	}
+	rep := fuzz.Evaluate(plan, res)
+
+	writeResults(artifactOptions{
+		outDir: *outDir, name: suite.Name,
+		json: *jsonOut, junit: *junitOut, html: *htmlOut,
+	}, res)
+	out := filepath.Join(*outDir, "fuzz.json")
+	writeOrDie(out, func(f *os.File) error {
+		return fuzz.WriteJSON(f, rep)
+	})
+
+	// Console summary
+	fmt.Printf("Fuzz (seed %d)\n", rep.Seed)
+	for _, op := range rep.Operations {
+		fmt.Printf("  %s %s: %d case(s), %d failed\n", op.Method, op.Path, op.Total, op.Failed)
+		for _, c := range op.Results {
+			if !c.Passed {
+				fmt.Printf("    ! %s: %s\n", c.ID, c.Error)
+			}
+		}
+	}
+	fmt.Printf("wrote %s\n", out)
+	if !rep.Passed {
+		fmt.Printf("reproduce with --seed %d\n", rep.Seed)
+	}
+
+	if *verbose {
+		printFailures(res, true)
+	}
+	exitWithResult(rep.Passed)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sea-qa/internal/contract"
+	"sea-qa/internal/generate"
+	"sea-qa/internal/ir"
+	"sea-qa/internal/parser"
+)
+
+// seaqa generate --openapi spec.yaml [--out suite.yaml]
+func runGenerate(args []string) {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	openapiPath := fs.String("openapi", "", "Path to OpenAPI (YAML/JSON) to generate from")
+	out := fs.String("out", "-", "Output suite file ('-' for stdout)")
+	name := fs.String("name", "", "Suite name (default: '<info.title> (generated)')")
+	baseVar := fs.String("base-var", "BASE_URL", "Variable used as URL prefix in generated requests")
+	examples := fs.Bool("examples", false, "One scenario per documented request example instead of per operation")
+	_ = fs.Parse(args)
+
+	if *openapiPath == "" {
+		fail("generate: missing --openapi")
+	}
+	v, err := contract.LoadFromFile(*openapiPath)
+	if err != nil {
+		fail("openapi load: %v", err)
+	}
+
+	opt := generate.Options{
+		Name:    *name,
+		OpenAPI: specRef(*openapiPath, *out),
+		BaseVar: *baseVar,
+	}
+	var suite *ir.TestSuite
+	if *examples {
+		var skipped []generate.Skipped
+		suite, skipped = generate.FromExamples(v.Doc(), opt)
+		for _, s := range skipped {
+			fmt.Fprintf(os.Stderr, "skipped %s %s: %s\n", s.Method, s.Path, s.Reason)
+		}
+	} else {
+		suite = generate.FromDoc(v.Doc(), opt)
+	}
+	if len(suite.Scenarios) == 0 {
+		fail("generate: spec has no operations")
+	}
+	writeSuite(*out, suite)
+}
+
+// specRef makes the suite's openapi: path relative to where the suite is
+// written, since the runner resolves it against the suite's directory.
+func specRef(specPath, suitePath string) string {
+	if suitePath == "-" || suitePath == "" {
+		return specPath
+	}
+	absSpec, err1 := filepath.Abs(specPath)
+	absDir, err2 := filepath.Abs(filepath.Dir(suitePath))
+	if err1 != nil || err2 != nil {
+		return specPath
+	}
+	rel, err := filepath.Rel(absDir, absSpec)
+	if err != nil {
+		return absSpec
+	}
+	return filepath.ToSlash(rel)
+}
+
+func writeSuite(path string, suite *ir.TestSuite) {
+	data, err := parser.Marshal(suite)
+	if err != nil {
+		fail("marshal suite: %v", err)
+	}
+	if path == "-" || path == "" {
+		_, _ = os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		fail("write %s: %v", path, err)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"sea-qa/internal/curl"
+	"sea-qa/internal/har"
+	"sea-qa/internal/postman"
+)
+
+const importUsage = `usage:
+  seaqa import har <file.har> [--out suite.yaml] [--name NAME] [--base-var VAR] [--all]
+  seaqa import postman <collection.json> [--out suite.yaml] [--env env.postman_environment.json] [--env-out env.json]
+  seaqa import curl [commands.sh|-] [--out suite.yaml] [--name NAME] [--base-var VAR]`
+
+func runImport(args []string) {
+	if len(args) == 0 {
+		fail("%s", importUsage)
+	}
+	switch args[0] {
+	case "har":
+		importHAR(args[1:])
+	case "postman":
+		importPostman(args[1:])
+	case "curl":
+		importCurl(args[1:])
+	default:
+		fail("%s", importUsage)
+	}
+}
+
+// seaqa import har session.har [--out suite.yaml] [--name ...] [--all]
+func importHAR(args []string) {
+	fs := flag.NewFlagSet("import har", flag.ExitOnError)
+	out := fs.String("out", "-", "Suite file to write ('-' for stdout)")
+	name := fs.String("name", "", "Suite name (default \"har import\")")
+	baseVar := fs.String("base-var", "BASE_URL", "Variable that replaces the most frequent origin")
+	all := fs.Bool("all", false, "Keep static assets (documents, scripts, styles, images, fonts)")
+	file := parseWithFile(fs, args, "import har")
+
+	f, err := har.Load(file)
+	if err != nil {
+		fail("%v", err)
+	}
+	suite := har.ToSuite(f, har.ImportOptions{Name: *name, BaseVar: *baseVar, All: *all})
+	if len(suite.Scenarios[0].Steps) == 0 {
+		fail("import har: no API requests in %s (use --all to keep static assets)", file)
+	}
+	writeSuite(*out, suite)
+	if *out != "-" {
+		fmt.Fprintf(os.Stderr, "imported %d request(s)\nwrote %s\n", len(suite.Scenarios[0].Steps), *out)
+	}
+}
+
+// seaqa import postman collection.json [--env env.json --env-out env/dev.json]
+func importPostman(args []string) {
+	fs := flag.NewFlagSet("import postman", flag.ExitOnError)
+	out := fs.String("out", "-", "Suite file to write ('-' for stdout)")
+	envIn := fs.String("env", "", "Postman environment export to convert")
+	envOut := fs.String("env-out", "", "Env JSON to write (collection variables plus --env values)")
+	file := parseWithFile(fs, args, "import postman")
+
+	c, err := postman.LoadCollection(file)
+	if err != nil {
+		fail("%v", err)
+	}
+	suite, notes := postman.ToSuite(c)
+	if len(suite.Scenarios) == 0 {
+		fail("import postman: no requests in %s", file)
+	}
+
+	env := c.Vars()
+	if *envIn != "" {
+		e, err := postman.LoadEnvironment(*envIn)
+		if err != nil {
+			fail("%v", err)
+		}
+		for k, v := range e.Vars() {
+			env[k] = v // environment overrides collection variables
+		}
+	}
+	if *envOut != "" {
+		b, err := json.MarshalIndent(env, "", "  ")
+		if err != nil {
+			fail("encode env: %v", err)
+		}
+		if err := os.WriteFile(*envOut, append(b, '\n'), 0o644); err != nil {
+			fail("write %s: %v", *envOut, err)
+		}
+	} else if len(env) > 0 {
+		notes = append(notes, postman.Note{Reason: fmt.Sprintf("%d variable(s) not written; pass --env-out", len(env))})
+	}
+
+	writeSuite(*out, suite)
+	for _, n := range notes {
+		if n.Item == "" {
+			fmt.Fprintf(os.Stderr, "not translated: %s\n", n.Reason)
+		} else {
+			fmt.Fprintf(os.Stderr, "not translated: %s: %s\n", n.Item, n.Reason)
+		}
+	}
+	if *out != "-" {
+		steps := 0
+		for _, sc := range suite.Scenarios {
+			steps += len(sc.Steps)
+		}
+		fmt.Fprintf(os.Stderr, "imported %d request(s) in %d scenario(s)\nwrote %s\n", steps, len(suite.Scenarios), *out)
+	}
+	if *envOut != "" {
+		fmt.Fprintf(os.Stderr, "wrote %s\n", *envOut)
+	}
+}
+
+// seaqa import curl requests.sh   (or paste into stdin)
+func importCurl(args []string) {
+	fs := flag.NewFlagSet("import curl", flag.ExitOnError)
+	out := fs.String("out", "-", "Suite file to write ('-' for stdout)")
+	name := fs.String("name", "", "Suite name (default \"curl import\")")
+	baseVar := fs.String("base-var", "BASE_URL", "Variable that replaces the most frequent origin")
+	files := parseFiles(fs, args)
+	if len(files) > 1 {
+		fail("import curl: expected at most one input file")
+	}
+
+	var (
+		text []byte
+		err  error
+	)
+	if len(files) == 0 || files[0] == "-" {
+		text, err = io.ReadAll(os.Stdin)
+	} else {
+		text, err = os.ReadFile(files[0])
+	}
+	if err != nil {
+		fail("read curl commands: %v", err)
+	}
+	suite, notes, err := curl.ToSuite(string(text), curl.ImportOptions{Name: *name, BaseVar: *baseVar})
+	if err != nil {
+		fail("import curl: %v", err)
+	}
+	writeSuite(*out, suite)
+	for _, n := range notes {
+		fmt.Fprintf(os.Stderr, "command %d: %s\n", n.Command, n.Reason)
+	}
+	if *out != "-" {
+		fmt.Fprintf(os.Stderr, "imported %d request(s)\nwrote %s\n", len(suite.Scenarios[0].Steps), *out)
+	}
+}
+
+// parseWithFile parses flags given before or after the single positional
+// file argument and returns that file.
+func parseWithFile(fs *flag.FlagSet, args []string, cmd string) string {
+	files := parseFiles(fs, args)
+	if len(files) != 1 {
+		fail("%s: expected exactly one input file", cmd)
+	}
+	return files[0]
+}
+
+// parseFiles parses flags interleaved with positional arguments.
+func parseFiles(fs *flag.FlagSet, args []string) []string {
+	var files []string
+	for {
+		_ = fs.Parse(args)
+		if fs.NArg() == 0 {
+			return files
+		}
+		files = append(files, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sea-qa/internal/executor"
+	"sea-qa/internal/har"
+	"sea-qa/internal/infer"
+)
+
+// seaqa infer --from reports/results.json[,session.har] [--out openapi.yaml]
+func runInfer(args []string) {
+	fs := flag.NewFlagSet("infer", flag.ExitOnError)
+	from := fs.String("from", "", "Comma-separated results.json and/or .har files")
+	out := fs.String("out", "-", "OpenAPI file to write ('-' for stdout)")
+	title := fs.String("title", "", "info.title (default \"Inferred API\")")
+	version := fs.String("version", "", "info.version (default 0.0.0)")
+	_ = fs.Parse(args)
+
+	if *from == "" {
+		fail("infer: missing --from")
+	}
+	var obs []infer.Observation
+	for _, p := range splitCSV(*from) {
+		if strings.EqualFold(filepath.Ext(p), ".har") {
+			f, err := har.Load(p)
+			if err != nil {
+				fail("%v", err)
+			}
+			obs = append(obs, infer.FromHAR(f)...)
+			continue
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			fail("read results: %v", err)
+		}
+		var res executor.SuiteResult
+		if err := json.Unmarshal(b, &res); err != nil {
+			fail("decode %s: %v", p, err)
+		}
+		obs = append(obs, infer.FromResults(&res)...)
+	}
+	if len(obs) == 0 {
+		fail("infer: no requests with responses in %s", *from)
+	}
+
+	doc := infer.Infer(obs, infer.Options{Title: *title, Version: *version})
+	data, err := infer.Marshal(doc)
+	if err != nil {
+		fail("%v", err)
+	}
+	if *out == "-" {
+		_, _ = os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		fail("write %s: %v", *out, err)
+	}
+	fmt.Fprintf(os.Stderr, "inferred %d path(s) from %d request(s)\nwrote %s\n", doc.Paths.Len(), len(obs), *out)
+}
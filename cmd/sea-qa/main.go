@@ -6,8 +6,12 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"sort"
 	"strings"
+	"syscall"
+	"time"
 
 	"sea-qa/internal/contract"
 	"sea-qa/internal/executor"
@@ -18,6 +22,35 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "generate":
+			runGenerate(os.Args[2:])
+			return
+		case "fuzz":
+			runFuzz(os.Args[2:])
+			return
+		case "examples":
+			runExamples(os.Args[2:])
+			return
+		case "mock":
+			runMock(os.Args[2:])
+			return
+		case "record":
+			runRecord(os.Args[2:])
+			return
+		case "replay":
+			runReplay(os.Args[2:])
+			return
+		case "import":
+			runImport(os.Args[2:])
+			return
+		case "infer":
+			runInfer(os.Args[2:])
+			return
+		}
+	}
+
 	var (
 		// run mode
 		spec        = flag.String("spec", "", "Path to YAML/JSON test suite")
@@ -27,6 +60,7 @@ func main() {
 		jsonOut     = flag.Bool("json", true, "Write JSON results")
 		junitOut    = flag.Bool("junit", true, "Write JUnit XML results")
 		htmlOut     = flag.Bool("html", true, "Write HTML report")
+		harOut      = flag.Bool("har", false, "Write run.har with every executed request/response")
 		verbose     = flag.Bool("v", false, "Verbose: print failure details")
 		openapiPath = flag.String("openapi", "", "Path to OpenAPI (YAML/JSON) for contract checks & coverage")
 		covMin      = flag.Float64("coverage-min", -1, "Fail if coverage percent < this threshold (requires OpenAPI)")
@@ -36,9 +70,13 @@ func main() {
 		excludeTags = flag.String("exclude-tags", "", "Comma-separated tags to exclude (OR semantics)")
 
 		// diff mode
-		diffA = flag.String("diff-a", "", "Contract diff: path to OpenAPI A (enables diff mode)")
-		diffB = flag.String("diff-b", "", "Contract diff: path to OpenAPI B (enables diff mode)")
+		diffA      = flag.String("diff-a", "", "Contract diff: OpenAPI A as a path or git:<rev>:<path> (enables diff mode)")
+		diffB      = flag.String("diff-b", "", "Contract diff: OpenAPI B as a path or git:<rev>:<path> (enables diff mode)")
+		diffIgnore = flag.String("diff-ignore", "", "Contract diff: YAML file of accepted changes (by id/operation, with expiry)")
+		failOn     = flag.String("fail-on", "", "Contract diff: exit 1 on 'breaking' or 'any' change")
+		mdOut      = flag.Bool("markdown", true, "Contract diff: write contract-diff.md")
 	)
+	redaction := redactionFlags(flag.CommandLine)
 	flag.Parse()
 
 	// ---- Contract diff mode (no --spec required) ----
@@ -46,7 +84,16 @@ func main() {
 		if *diffA == "" || *diffB == "" {
 			fail("both --diff-a and --diff-b are required for contract diff mode")
 		}
-		runContractDiff(*diffA, *diffB, *outDir)
+		switch *failOn {
+		case "", "breaking", "any":
+		default:
+			fail("--fail-on must be 'breaking' or 'any', got %q", *failOn)
+		}
+		runContractDiff(diffOptions{
+			a: *diffA, b: *diffB, outDir: *outDir,
+			ignorePath: *diffIgnore, failOn: *failOn,
+			markdown: *mdOut, html: *htmlOut,
+		})
 		return
 	}
 
@@ -108,157 +155,182 @@ func main() {
 	}
 
 	// Runner
-	r := executor.NewWithVars(baseVars).WithParallel(*parallel).WithFailFast(*failFast)
+	r := executor.NewWithVars(baseVars).WithParallel(*parallel).WithFailFast(*failFast).WithRedaction(redaction())
 
-	// Contract (strict)
-	var v *contract.Validator
-	if openapiFile != "" {
-		v, err = contract.LoadFromFile(openapiFile)
-		if err != nil {
-			fail("openapi load: %v", err)
-		}
-		r = r.WithContract(v)
-	}
-
-	// Execute
-	res, err := r.RunSuite(context.Background(), suite)
+	// Contract (strict): default spec plus any per-service specs from the suite
+	specs, err := loadSpecs(openapiFile, suite, filepath.Dir(*spec))
 	if err != nil {
-		fail("execute: %v", err)
+		fail("%v", err)
 	}
-
-	// Artifacts
-	if err := os.MkdirAll(*outDir, 0o755); err != nil {
-		fail("mkdir out: %v", err)
-	}
-
-	// Always compute suite name for outputs
-	outSuiteName := suite.Name
-	if outSuiteName == "" {
-		outSuiteName = "sea-qa"
+	if specs.Len() > 0 {
+		r = r.WithContracts(specs)
 	}
 
-	// JSON (and remember the path for HTML parity)
-	var jsonPath string
-	if *jsonOut {
-		jsonPath = filepath.Join(*outDir, "results.json")
-		writeOrDie(jsonPath, func(f *os.File) error {
-			return reporter.WriteJSON(f, res)
-		})
+	// Execute. Hooks run in their own process group, so an interrupt cancels
+	// the run, which kills them, instead of reaching them directly.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	res, err := r.RunSuite(ctx, suite)
+	stop()
+	if cerr := r.Close(); cerr != nil {
+		fmt.Fprintf(os.Stderr, "hooks: %v\n", cerr)
 	}
-
-	// JUnit
-	if *junitOut {
-		writeOrDie(filepath.Join(*outDir, "junit.xml"), func(f *os.File) error {
-			return reporter.WriteJUnit(f, outSuiteName, res)
-		})
+	if err != nil {
+		fail("execute: %v", err)
 	}
 
-	// HTML — if JSON is enabled, render from results.json to guarantee parity
-	if *htmlOut {
-		htmlPath := filepath.Join(*outDir, "report.html")
-		if *jsonOut && jsonPath != "" {
-			writeOrDie(htmlPath, func(f *os.File) error {
-				return reporter.WriteHTMLFromJSONPath(f, outSuiteName, jsonPath)
-			})
-		} else {
-			// fallback: render directly from memory
-			writeOrDie(htmlPath, func(f *os.File) error {
-				return reporter.WriteHTML(f, outSuiteName, res)
-			})
-		}
-	}
+	// Artifacts
+	writeResults(artifactOptions{
+		outDir: *outDir, name: suite.Name,
+		json: *jsonOut, junit: *junitOut, html: *htmlOut, har: *harOut,
+	}, res)
 
 	// Coverage report + optional gate
-	if v != nil {
-		writeOrDie(filepath.Join(*outDir, "coverage.json"), func(f *os.File) error {
-			return reporter.WriteCoverage(f, v.Doc(), r.Covered())
-		})
-		if *covMin >= 0 {
-			rep := reporter.ComputeCoverage(v.Doc(), r.Covered())
-			if rep.Percent+1e-9 < *covMin {
-				fmt.Fprintf(os.Stderr, "coverage gate failed: got %.2f%%, need >= %.2f%%\n", rep.Percent, *covMin)
-				fmt.Println("FAIL")
-				os.Exit(1)
-			}
-		}
-	}
-
-	// Failure summary (or verbose print)
-	if !res.Passed || *verbose {
-		for _, sc := range res.Scenarios {
-			if sc.Passed {
-				continue
-			}
-			fmt.Fprintf(os.Stderr, "\nScenario FAILED: %s\n", sc.Name)
-			for i, st := range sc.Steps {
-				if st.Passed {
-					continue
-				}
-				fmt.Fprintf(os.Stderr, "  Step %d: status=%d\n", i+1, st.StatusCode)
-				for _, e := range st.Errors {
-					fmt.Fprintf(os.Stderr, "    - %s\n", e)
-				}
-			}
+	if specs.Len() > 0 {
+		percent := writeCoverage(*outDir, specs, r)
+		writeDrift(*outDir, res)
+		if *covMin >= 0 && percent+1e-9 < *covMin {
+			fmt.Fprintf(os.Stderr, "coverage gate failed: got %.2f%%, need >= %.2f%%\n", percent, *covMin)
+			fmt.Println("FAIL")
+			os.Exit(1)
 		}
 	}
 
-	if res.Passed {
-		fmt.Println("PASS")
-		os.Exit(0)
-	}
-	fmt.Println("FAIL")
-	os.Exit(1)
+	printFailures(res, *verbose)
+	exitWithResult(res.Passed)
 }
 
 // ---- Contract diff mode ----
 
-func runContractDiff(aPath, bPath, outDir string) {
-	if err := os.MkdirAll(outDir, 0o755); err != nil {
+type diffOptions struct {
+	a, b       string
+	outDir     string
+	ignorePath string
+	failOn     string // "", "breaking" or "any"
+	markdown   bool
+	html       bool
+}
+
+func runContractDiff(o diffOptions) {
+	if err := os.MkdirAll(o.outDir, 0o755); err != nil {
 		fail("mkdir out: %v", err)
 	}
-	a, err := contract.LoadFromFile(aPath)
+	a, err := contract.LoadFromRef(o.a)
 	if err != nil {
 		fail("openapi A load: %v", err)
 	}
-	b, err := contract.LoadFromFile(bPath)
+	b, err := contract.LoadFromRef(o.b)
 	if err != nil {
 		fail("openapi B load: %v", err)
 	}
 
 	rep := contract.DiffDocs(a.Doc(), b.Doc())
 
-	out := filepath.Join(outDir, "contract-diff.json")
+	if o.ignorePath != "" {
+		rules, err := contract.LoadIgnoreFile(o.ignorePath)
+		if err != nil {
+			fail("%v", err)
+		}
+		for _, r := range rep.ApplyIgnores(rules, time.Now()) {
+			fmt.Fprintf(os.Stderr, "warning: ignore rule expired on %s (id=%q operation=%q)\n", r.Expires, r.ID, r.Operation)
+		}
+	}
+
+	out := filepath.Join(o.outDir, "contract-diff.json")
 	writeOrDie(out, func(f *os.File) error {
 		enc := json.NewEncoder(f)
 		enc.SetIndent("", "  ")
 		return enc.Encode(rep)
 	})
+	written := []string{out}
+
+	title := fmt.Sprintf("Contract diff: %s → %s", o.a, o.b)
+	if o.markdown {
+		p := filepath.Join(o.outDir, "contract-diff.md")
+		writeOrDie(p, func(f *os.File) error {
+			return reporter.WriteDiffMarkdown(f, title, rep)
+		})
+		written = append(written, p)
+	}
+	if o.html {
+		p := filepath.Join(o.outDir, "contract-diff.html")
+		writeOrDie(p, func(f *os.File) error {
+			return reporter.WriteDiffHTML(f, title, rep)
+		})
+		written = append(written, p)
+	}
 
 	// Console summary
-	fmt.Printf("Contract diff (%s → %s)\n", aPath, bPath)
-	if len(rep.Added) == 0 && len(rep.Removed) == 0 && len(rep.ChangedStatus) == 0 {
+	fmt.Printf("Contract diff (%s → %s)\n", o.a, o.b)
+	if len(rep.Changes) == 0 {
 		fmt.Println("  No changes.")
 	} else {
-		if len(rep.Added) > 0 {
-			fmt.Println("  Added:")
-			for _, op := range rep.Added {
-				fmt.Printf("    + %s %s\n", op.Method, op.Path)
+		for _, ch := range rep.Changes {
+			mark := " "
+			if ch.Breaking {
+				mark = "!"
 			}
+			fmt.Printf("  %s %s %s: %s [%s]\n", mark, ch.Method, ch.Path, ch.Message, ch.ID)
 		}
-		if len(rep.Removed) > 0 {
-			fmt.Println("  Removed:")
-			for _, op := range rep.Removed {
-				fmt.Printf("    - %s %s\n", op.Method, op.Path)
-			}
+		fmt.Printf("  %d change(s), %d breaking\n", len(rep.Changes), rep.Breaking)
+	}
+	if len(rep.Ignored) > 0 {
+		fmt.Printf("  %d change(s) ignored\n", len(rep.Ignored))
+	}
+	for _, p := range written {
+		fmt.Printf("wrote %s\n", p)
+	}
+
+	if (o.failOn == "breaking" && rep.HasBreaking()) || (o.failOn == "any" && len(rep.Changes) > 0) {
+		fmt.Println("FAIL")
+		os.Exit(1)
+	}
+}
+
+// ---- OpenAPI specs ----
+
+// loadSpecs builds the contract registry: the default spec (--openapi or
+// suite.openapi) matches everything, suite.openapis entries match by
+// host/base_url/prefix. Relative files resolve against the suite's directory.
+func loadSpecs(defaultFile string, suite *ir.TestSuite, suiteDir string) (*contract.Registry, error) {
+	reg := contract.NewRegistry()
+	if defaultFile != "" {
+		v, err := contract.LoadFromFile(defaultFile)
+		if err != nil {
+			return nil, fmt.Errorf("openapi load: %w", err)
 		}
-		if len(rep.ChangedStatus) > 0 {
-			fmt.Println("  Status changes:")
-			for _, ch := range rep.ChangedStatus {
-				fmt.Printf("    * %s %s: %v -> %v\n", ch.Method, ch.Path, ch.A, ch.B)
+		name := ""
+		if len(suite.OpenAPIs) > 0 {
+			if _, ok := suite.OpenAPIs[ir.DefaultSpec]; ok {
+				return nil, fmt.Errorf("openapis.%s clashes with the --openapi spec", ir.DefaultSpec)
 			}
+			name = ir.DefaultSpec
+		}
+		reg.Add(name, contract.Match{}, v)
+	}
+
+	names := make([]string, 0, len(suite.OpenAPIs))
+	for n := range suite.OpenAPIs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		sp := suite.OpenAPIs[n]
+		file := sp.File
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(suiteDir, file)
+		}
+		v, err := contract.LoadFromFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("openapi load %s: %w", n, err)
 		}
+		reg.Add(n, contract.Match{
+			Host:        sp.Host,
+			BaseURL:     sp.BaseURL,
+			Prefix:      sp.Prefix,
+			StripPrefix: sp.StripPrefix,
+		}, v)
 	}
-	fmt.Printf("wrote %s\n", out)
+	return reg, nil
 }
 
 // ---- helpers ----
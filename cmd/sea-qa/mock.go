@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"strings"
+
+	"sea-qa/internal/contract"
+	"sea-qa/internal/mock"
+)
+
+// seaqa mock --openapi spec.yaml [--addr :8081] [--stateful] [--fixtures users.yaml]
+func runMock(args []string) {
+	fs := flag.NewFlagSet("mock", flag.ExitOnError)
+	openapiPath := fs.String("openapi", "", "Path to OpenAPI (YAML/JSON) to mock")
+	addr := fs.String("addr", ":8081", "Listen address")
+	validate := fs.Bool("validate", true, "Reject requests that do not match the spec with 400")
+	cors := fs.Bool("cors", true, "Allow cross-origin requests (for browser clients)")
+	quiet := fs.Bool("quiet", false, "Do not log requests")
+	stateful := fs.Bool("stateful", false, "Keep created resources in memory (POST then GET returns them)")
+	fixtures := fs.String("fixtures", "", "Comma-separated fixture files/dirs seeding the store (implies --stateful)")
+	_ = fs.Parse(args)
+
+	if *openapiPath == "" {
+		fail("mock: missing --openapi")
+	}
+	v, err := contract.LoadFromFile(*openapiPath)
+	if err != nil {
+		fail("openapi load: %v", err)
+	}
+	opt := mock.Options{Validate: *validate, CORS: *cors}
+	if !*quiet {
+		opt.Log = log.Default()
+	}
+	if *stateful || *fixtures != "" {
+		var fx mock.Fixtures
+		if *fixtures != "" {
+			fx, err = mock.LoadFixtures(strings.Split(*fixtures, ","))
+			if err != nil {
+				fail("%v", err)
+			}
+		}
+		opt.Store = mock.NewStore(fx)
+	}
+	srv, err := mock.New(v, opt)
+	if err != nil {
+		fail("mock: %v", err)
+	}
+	log.Printf("mocking %s on %s", *openapiPath, *addr)
+	if err := http.ListenAndServe(*addr, srv); err != nil {
+		fail("mock: %v", err)
+	}
+}
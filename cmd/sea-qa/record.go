@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"sea-qa/internal/record"
+)
+
+// seaqa record --target http://localhost:8080 --listen :9000 [--out recorded.yaml]
+// Proxies until interrupted, then writes the suite and its cassette.
+func runRecord(args []string) {
+	fs := flag.NewFlagSet("record", flag.ExitOnError)
+	target := fs.String("target", "", "Upstream base URL to proxy to")
+	listen := fs.String("listen", ":9000", "Proxy listen address")
+	out := fs.String("out", "recorded.yaml", "Suite file to write on exit")
+	cassette := fs.String("cassette", "", "Cassette for replay (default: <out>.cassette.json)")
+	name := fs.String("name", "recorded", "Suite name")
+	baseVar := fs.String("base-var", "BASE_URL", "Variable that replaces the target/proxy origin")
+	_ = fs.Parse(args)
+
+	if *target == "" {
+		fail("record: missing --target")
+	}
+	rec, err := record.New(*target)
+	if err != nil {
+		fail("record: %v", err)
+	}
+	if *cassette == "" {
+		*cassette = strings.TrimSuffix(*out, filepath.Ext(*out)) + ".cassette.json"
+	}
+
+	serveUntilSignal(*listen, rec, fmt.Sprintf("recording %s on %s (Ctrl-C to stop)", *target, *listen))
+
+	if rec.Len() == 0 {
+		fail("record: nothing was recorded")
+	}
+	writeSuite(*out, rec.Suite(*name, *baseVar))
+	if err := record.WriteCassette(*cassette, rec.Cassette()); err != nil {
+		fail("%v", err)
+	}
+	fmt.Printf("recorded %d exchange(s)\nwrote %s\nwrote %s\n", rec.Len(), *out, *cassette)
+}
+
+// seaqa replay --cassette recorded.cassette.json --listen :9000
+func runReplay(args []string) {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	cassette := fs.String("cassette", "", "Cassette written by 'seaqa record'")
+	listen := fs.String("listen", ":9000", "Listen address")
+	_ = fs.Parse(args)
+
+	if *cassette == "" {
+		fail("replay: missing --cassette")
+	}
+	c, err := record.LoadCassette(*cassette)
+	if err != nil {
+		fail("%v", err)
+	}
+	serveUntilSignal(*listen, record.NewReplayer(c),
+		fmt.Sprintf("replaying %d exchange(s) on %s", len(c.Exchanges), *listen))
+}
+
+func serveUntilSignal(addr string, h http.Handler, banner string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{Addr: addr, Handler: h}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	log.Print(banner)
+
+	select {
+	case err := <-errc:
+		if !errors.Is(err, http.ErrServerClosed) {
+			fail("listen: %v", err)
+		}
+	case <-ctx.Done():
+		_ = srv.Shutdown(context.Background())
+	}
+}
@@ -0,0 +1,164 @@
+// Package assertion evaluates boolean expressions (expr-lang syntax) over a
+// response and explains failures by showing the values of sub-expressions.
+package assertion
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/parser"
+)
+
+// Response is what an expression can see.
+type Response struct {
+	Status  int
+	Headers map[string][]string
+	Body    []byte
+	Vars    map[string]string
+}
+
+// Env returns the expression environment: status, headers (first value per
+// canonical name), body (parsed JSON, or the raw string), vars, and
+// header(name) for case-insensitive lookups.
+func (r Response) Env() map[string]any {
+	headers := map[string]any{}
+	for k, vs := range r.Headers {
+		if len(vs) > 0 {
+			headers[http.CanonicalHeaderKey(k)] = vs[0]
+		}
+	}
+	var body any
+	if len(r.Body) > 0 {
+		if err := json.Unmarshal(r.Body, &body); err != nil {
+			body = string(r.Body)
+		}
+	}
+	vars := map[string]any{}
+	for k, v := range r.Vars {
+		vars[k] = v
+	}
+	return map[string]any{
+		"status":  r.Status,
+		"headers": headers,
+		"body":    body,
+		"vars":    vars,
+		"header": func(name string) any {
+			return headers[http.CanonicalHeaderKey(name)]
+		},
+	}
+}
+
+// Check evaluates src against env. It returns "" when the expression is
+// true; otherwise a message naming the expression and, for a false result,
+// the values of the sub-expressions that made it false.
+func Check(src string, env map[string]any) string {
+	src = strings.TrimSpace(src)
+	if src == "" {
+		return "expr: empty expression"
+	}
+	got, err := eval(src, env)
+	if err != nil {
+		return fmt.Sprintf("expr %s: %v", src, err)
+	}
+	b, ok := got.(bool)
+	if !ok {
+		return fmt.Sprintf("expr %s: result is %T, not bool", src, got)
+	}
+	if b {
+		return ""
+	}
+	tree, err := parser.Parse(src)
+	if err != nil {
+		return fmt.Sprintf("expr %s: false", src)
+	}
+	var why []string
+	explain(tree.Node, env, &why)
+	if len(why) == 0 {
+		return fmt.Sprintf("expr %s: false", src)
+	}
+	return fmt.Sprintf("expr %s: false (%s)", src, strings.Join(why, "; "))
+}
+
+func eval(src string, env map[string]any) (any, error) {
+	prog, err := expr.Compile(src)
+	if err != nil {
+		return nil, err
+	}
+	return expr.Run(prog, env)
+}
+
+// explain walks the false branch of n and records the values behind it.
+func explain(n ast.Node, env map[string]any, why *[]string) {
+	switch x := n.(type) {
+	case *ast.BinaryNode:
+		switch x.Operator {
+		case "&&", "and":
+			if v, _ := eval(x.Left.String(), env); v != true {
+				explain(x.Left, env, why)
+				return
+			}
+			explain(x.Right, env, why)
+			return
+		case "||", "or":
+			explain(x.Left, env, why)
+			explain(x.Right, env, why)
+			return
+		}
+		show(x.Left, env, why)
+		show(x.Right, env, why)
+	case *ast.UnaryNode:
+		if x.Operator == "!" || x.Operator == "not" {
+			show(x.Node, env, why)
+			return
+		}
+		show(x, env, why)
+	case *ast.BuiltinNode:
+		if (x.Name == "all" || x.Name == "none") && len(x.Arguments) == 2 {
+			pred := fmt.Sprintf("not (%s)", x.Arguments[1])
+			if x.Name == "none" {
+				pred = x.Arguments[1].String()
+			}
+			arr := x.Arguments[0].String()
+			if i, err := eval(fmt.Sprintf("findIndex(%s, %s)", arr, pred), env); err == nil {
+				if idx, ok := i.(int); ok && idx >= 0 {
+					item := fmt.Sprintf("%s[%d]", arr, idx)
+					v, _ := eval(item, env)
+					*why = append(*why, item+" = "+format(v))
+					return
+				}
+			}
+		}
+		show(x, env, why)
+	default:
+		show(n, env, why)
+	}
+}
+
+// show records "expr = value" for anything but literals.
+func show(n ast.Node, env map[string]any, why *[]string) {
+	switch n.(type) {
+	case *ast.NilNode, *ast.IntegerNode, *ast.FloatNode, *ast.BoolNode, *ast.StringNode, *ast.ConstantNode:
+		return
+	}
+	v, err := eval(n.String(), env)
+	if err != nil {
+		*why = append(*why, fmt.Sprintf("%s: %v", n, err))
+		return
+	}
+	*why = append(*why, n.String()+" = "+format(v))
+}
+
+func format(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	if s := string(b); len(s) <= 120 {
+		return s
+	}
+	return string(b[:117]) + "..."
+}
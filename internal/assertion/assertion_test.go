@@ -0,0 +1,54 @@
+package assertion_test
+
+import (
+	"strings"
+	"testing"
+
+	"sea-qa/internal/assertion"
+)
+
+func env() map[string]any {
+	return assertion.Response{
+		Status:  200,
+		Headers: map[string][]string{"content-type": {"application/json"}},
+		Body:    []byte(`{"total":3,"items":[{"id":"a","price":5},{"id":"b","price":-1},{"id":"c","price":2}]}`),
+		Vars:    map[string]string{"MIN": "1"},
+	}.Env()
+}
+
+func TestCheckPasses(t *testing.T) {
+	for _, src := range []string{
+		`status == 200`,
+		`len(body.items) == body.total`,
+		`any(body.items, .id == "b")`,
+		`headers["Content-Type"] startsWith "application/json"`,
+		`header("CONTENT-TYPE") == "application/json"`,
+		`vars.MIN == "1"`,
+		`body.missing?.field == nil`,
+	} {
+		if msg := assertion.Check(src, env()); msg != "" {
+			t.Errorf("%s: %s", src, msg)
+		}
+	}
+}
+
+func TestCheckExplainsFailures(t *testing.T) {
+	cases := []struct{ src, want string }{
+		{`len(body.items) > 0 && all(body.items, .price >= 0)`,
+			`(body.items[1] = {"id":"b","price":-1})`},
+		{`status == 201`, `(status = 200)`},
+		{`len(body.items) == body.total + 1`, `(len(body.items) = 3; body.total + 1 = 4)`},
+		{`status == 404 || body.total > 5`, `(status = 200; body.total = 3)`},
+		{`none(body.items, .id == "c")`, `(body.items[2] = {"id":"c","price":2})`},
+		{`not (status in [200, 204])`, `(status in [200, 204] = true)`},
+		{`body.total`, `result is float64, not bool`},
+		{`body.nope.deeper == 1`, `cannot fetch deeper`},
+		{`status ==`, `unexpected token`},
+	}
+	for _, tc := range cases {
+		msg := assertion.Check(tc.src, env())
+		if !strings.HasPrefix(msg, "expr "+tc.src) || !strings.Contains(msg, tc.want) {
+			t.Errorf("%s:\n got %s\nwant ...%s", tc.src, msg, tc.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Package capture holds the rules shared by everything that turns captured
+// HTTP traffic (recordings, HAR, Postman, curl) into suite requests.
+package capture
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"sea-qa/internal/redact"
+)
+
+var hopByHop = map[string]bool{
+	"Connection": true, "Keep-Alive": true, "Proxy-Authenticate": true,
+	"Proxy-Authorization": true, "Te": true, "Trailer": true,
+	"Transfer-Encoding": true, "Upgrade": true,
+}
+
+// noise is dropped from generated requests: transport details, browser
+// fingerprinting and session cookies that would not replay meaningfully.
+var noise = map[string]bool{
+	"Host": true, "Content-Length": true, "Accept-Encoding": true,
+	"User-Agent": true, "Cookie": true, "Origin": true, "Referer": true,
+	"Cache-Control": true, "Pragma": true, "Priority": true,
+	"If-None-Match": true, "If-Modified-Since": true,
+}
+
+// HopByHop reports whether a header applies to a single connection and must
+// not be forwarded.
+func HopByHop(name string) bool {
+	return hopByHop[http.CanonicalHeaderKey(name)]
+}
+
+// KeepHeader reports whether a captured request header belongs in a suite.
+func KeepHeader(name string) bool {
+	k := http.CanonicalHeaderKey(name)
+	if hopByHop[k] || noise[k] || strings.HasPrefix(k, ":") {
+		return false
+	}
+	return !strings.HasPrefix(k, "Sec-")
+}
+
+// credentials recognises credential headers the way result redaction does.
+var credentials, _ = redact.New()
+
+// Credential reports whether a header carries a credential.
+func Credential(name string) bool {
+	return credentials.SecretHeader(name)
+}
+
+// CredentialRef replaces a credential with a var: ${TOKEN} after the
+// Authorization scheme, ${X_API_KEY} for X-Api-Key and so on. Values that
+// already reference a var are returned unchanged.
+func CredentialRef(name, v string) string {
+	if strings.Contains(v, "${") {
+		return v
+	}
+	k := http.CanonicalHeaderKey(name)
+	if k == "Authorization" {
+		if scheme, _, ok := strings.Cut(v, " "); ok {
+			return scheme + " ${TOKEN}"
+		}
+		return "${TOKEN}"
+	}
+	return "${" + strings.ToUpper(strings.ReplaceAll(k, "-", "_")) + "}"
+}
+
+// ParseBody returns JSON bodies as values (so YAML stays readable) and
+// anything else as the raw string.
+func ParseBody(s string) any {
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err == nil {
+		switch v.(type) {
+		case map[string]any, []any:
+			return v
+		}
+	}
+	return s
+}
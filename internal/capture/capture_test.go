@@ -0,0 +1,60 @@
+package capture_test
+
+import (
+	"testing"
+
+	"sea-qa/internal/capture"
+)
+
+func TestKeepHeader(t *testing.T) {
+	for name, want := range map[string]bool{
+		"Content-Type":      true,
+		"authorization":     true,
+		"X-Request-Id":      true,
+		"connection":        false,
+		"Cookie":            false,
+		"Sec-Fetch-Mode":    false,
+		":authority":        false,
+		"accept-encoding":   false,
+		"Content-Length":    false,
+		"If-None-Match":     false,
+		"Transfer-Encoding": false,
+	} {
+		if got := capture.KeepHeader(name); got != want {
+			t.Errorf("KeepHeader(%q) = %v, want %v", name, got, want)
+		}
+	}
+	if !capture.HopByHop("keep-alive") || capture.HopByHop("Content-Type") {
+		t.Error("HopByHop misclassifies")
+	}
+}
+
+func TestParseBody(t *testing.T) {
+	if m, ok := capture.ParseBody(`{"a":1}`).(map[string]any); !ok || m["a"] != 1.0 {
+		t.Errorf("object body not decoded")
+	}
+	if _, ok := capture.ParseBody(`[1,2]`).([]any); !ok {
+		t.Errorf("array body not decoded")
+	}
+	for _, raw := range []string{`"str"`, `42`, `a=1&b=2`} {
+		if got := capture.ParseBody(raw); got != raw {
+			t.Errorf("ParseBody(%q) = %v, want raw string", raw, got)
+		}
+	}
+}
+
+func TestCredentialRef(t *testing.T) {
+	for _, c := range []struct{ name, in, want string }{
+		{"authorization", "Bearer abc", "Bearer ${TOKEN}"},
+		{"Authorization", "abc", "${TOKEN}"},
+		{"x-api-key", "k3y", "${X_API_KEY}"},
+		{"Authorization", "Bearer ${token}", "Bearer ${token}"},
+	} {
+		if got := capture.CredentialRef(c.name, c.in); got != c.want {
+			t.Errorf("CredentialRef(%q, %q) = %q, want %q", c.name, c.in, got, c.want)
+		}
+	}
+	if !capture.Credential("X-Api-Key") || capture.Credential("Content-Type") {
+		t.Error("Credential misclassifies")
+	}
+}
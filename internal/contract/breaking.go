@@ -0,0 +1,632 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Change ids. Stable strings: ignore files (see --diff-ignore) refer to them.
+const (
+	ChangeOperationRemoved         = "operation-removed"
+	ChangeOperationAdded           = "operation-added"
+	ChangeParamRemoved             = "request-parameter-removed"
+	ChangeParamAdded               = "request-parameter-added"
+	ChangeParamRequiredAdded       = "request-parameter-required-added"
+	ChangeParamBecameRequired      = "request-parameter-became-required"
+	ChangeParamBecameOptional      = "request-parameter-became-optional"
+	ChangeBodyBecameRequired       = "request-body-became-required"
+	ChangeBodyAdded                = "request-body-added"
+	ChangeBodyRemoved              = "request-body-removed"
+	ChangeReqMediaTypeRemoved      = "request-media-type-removed"
+	ChangeReqMediaTypeAdded        = "request-media-type-added"
+	ChangeReqPropertyRemoved       = "request-property-removed"
+	ChangeReqPropertyAdded         = "request-property-added"
+	ChangeReqPropertyRequiredAdded = "request-property-required-added"
+	ChangeReqPropertyBecameReq     = "request-property-became-required"
+	ChangeReqPropertyBecameOpt     = "request-property-became-optional"
+	ChangeReqTypeChanged           = "request-type-changed"
+	ChangeReqEnumNarrowed          = "request-enum-narrowed"
+	ChangeReqEnumWidened           = "request-enum-widened"
+	ChangeReqNullableRemoved       = "request-nullable-removed"
+	ChangeStatusRemoved            = "response-status-removed"
+	ChangeStatusAdded              = "response-status-added"
+	ChangeRespMediaTypeRemoved     = "response-media-type-removed"
+	ChangeRespMediaTypeAdded       = "response-media-type-added"
+	ChangeRespPropertyRemoved      = "response-property-removed"
+	ChangeRespPropertyAdded        = "response-property-added"
+	ChangeRespPropertyBecameOpt    = "response-property-became-optional"
+	ChangeRespTypeChanged          = "response-type-changed"
+	ChangeRespEnumWidened          = "response-enum-widened"
+	ChangeRespEnumNarrowed         = "response-enum-narrowed"
+	ChangeRespNullableAdded        = "response-nullable-added"
+	ChangeReqAdditionalPropsClosed = "request-additional-properties-disallowed"
+	ChangeRespAdditionalPropsOpen  = "response-additional-properties-allowed"
+	ChangeRespHeaderRemoved        = "response-header-removed"
+	ChangeRespHeaderAdded          = "response-header-added"
+	ChangeRespHeaderBecameOpt      = "response-header-became-optional"
+	ChangeSecurityAdded            = "security-alternative-added"
+	ChangeSecurityRemoved          = "security-alternative-removed"
+)
+
+// Change is a single classified difference between two documents.
+// Pointer is a JSON pointer into doc B (into doc A for removals).
+type Change struct {
+	ID       string `json:"id"`
+	Breaking bool   `json:"breaking"`
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Pointer  string `json:"pointer"`
+	Message  string `json:"message"`
+}
+
+type diffCtx struct {
+	op      OpSig
+	changes []Change
+	seen    map[[2]*openapi3.Schema]bool
+}
+
+func (c *diffCtx) add(id string, breaking bool, ptr, format string, args ...any) {
+	c.changes = append(c.changes, Change{
+		ID:       id,
+		Breaking: breaking,
+		Method:   c.op.Method,
+		Path:     c.op.Path,
+		Pointer:  ptr,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// deepChanges compares every operation present in both docs and classifies
+// the differences; added/removed operations are reported here too.
+func deepChanges(a, b *openapi3.T, added, removed []OpSig) []Change {
+	var out []Change
+	for _, op := range removed {
+		out = append(out, Change{ID: ChangeOperationRemoved, Breaking: true, Method: op.Method, Path: op.Path,
+			Pointer: opPointer(op), Message: "operation removed"})
+	}
+	for _, op := range added {
+		out = append(out, Change{ID: ChangeOperationAdded, Method: op.Method, Path: op.Path,
+			Pointer: opPointer(op), Message: "operation added"})
+	}
+
+	for _, op := range listOps(a) {
+		oa := operation(a, op)
+		ob := operation(b, op)
+		if oa == nil || ob == nil {
+			continue
+		}
+		c := &diffCtx{op: op, seen: map[[2]*openapi3.Schema]bool{}}
+		base := opPointer(op)
+		diffParams(c, paramsOf(a, op, oa), paramsOf(b, op, ob))
+		diffRequestBody(c, base+"/requestBody", oa.RequestBody, ob.RequestBody)
+		diffResponses(c, base+"/responses", oa.Responses, ob.Responses)
+		diffSecurity(c, base+"/security", securityOf(a, oa), securityOf(b, ob))
+		out = append(out, c.changes...)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Path != out[j].Path {
+			return out[i].Path < out[j].Path
+		}
+		if out[i].Method != out[j].Method {
+			return out[i].Method < out[j].Method
+		}
+		if out[i].Pointer != out[j].Pointer {
+			return out[i].Pointer < out[j].Pointer
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// ---- parameters ----
+
+type paramKey struct{ in, name string }
+
+type paramAt struct {
+	p   *openapi3.Parameter
+	ptr string // JSON pointer of the parameter (path- or operation-level)
+}
+
+func paramsOf(doc *openapi3.T, op OpSig, o *openapi3.Operation) map[paramKey]paramAt {
+	out := map[paramKey]paramAt{}
+	add := func(base string, ps openapi3.Parameters) {
+		for i, pr := range ps {
+			if pr == nil || pr.Value == nil {
+				continue
+			}
+			out[paramKey{pr.Value.In, pr.Value.Name}] = paramAt{pr.Value, fmt.Sprintf("%s/parameters/%d", base, i)}
+		}
+	}
+	if pi := doc.Paths.Value(op.Path); pi != nil {
+		add("/paths/"+escape(op.Path), pi.Parameters) // path-level first, operation-level overrides
+	}
+	add(opPointer(op), o.Parameters)
+	return out
+}
+
+func diffParams(c *diffCtx, a, b map[paramKey]paramAt) {
+	for k, pa := range a {
+		pb, ok := b[k]
+		if !ok {
+			c.add(ChangeParamRemoved, true, pa.ptr, "%s parameter %q removed", k.in, k.name)
+			continue
+		}
+		if !pa.p.Required && pb.p.Required {
+			c.add(ChangeParamBecameRequired, true, pb.ptr, "%s parameter %q became required", k.in, k.name)
+		}
+		if pa.p.Required && !pb.p.Required {
+			c.add(ChangeParamBecameOptional, false, pb.ptr, "%s parameter %q became optional", k.in, k.name)
+		}
+		diffSchema(c, pb.ptr+"/schema", schemaOf(pa.p.Schema), schemaOf(pb.p.Schema), true)
+	}
+	for k, pb := range b {
+		if _, ok := a[k]; ok {
+			continue
+		}
+		if pb.p.Required {
+			c.add(ChangeParamRequiredAdded, true, pb.ptr, "required %s parameter %q added", k.in, k.name)
+		} else {
+			c.add(ChangeParamAdded, false, pb.ptr, "optional %s parameter %q added", k.in, k.name)
+		}
+	}
+}
+
+// ---- request body ----
+
+func diffRequestBody(c *diffCtx, ptr string, a, b *openapi3.RequestBodyRef) {
+	var ba, bb *openapi3.RequestBody
+	if a != nil {
+		ba = a.Value
+	}
+	if b != nil {
+		bb = b.Value
+	}
+	switch {
+	case ba == nil && bb == nil:
+		return
+	case ba == nil:
+		c.add(ChangeBodyAdded, bb.Required, ptr, "request body added (required=%v)", bb.Required)
+		return
+	case bb == nil:
+		c.add(ChangeBodyRemoved, false, ptr, "request body removed")
+		return
+	}
+	if !ba.Required && bb.Required {
+		c.add(ChangeBodyBecameRequired, true, ptr, "request body became required")
+	}
+	diffContent(c, ptr+"/content", ba.Content, bb.Content, true)
+}
+
+// ---- responses ----
+
+func diffResponses(c *diffCtx, base string, a, b *openapi3.Responses) {
+	ma, mb := responsesMap(a), responsesMap(b)
+	for code, ra := range ma {
+		ptr := base + "/" + escape(code)
+		rb, ok := mb[code]
+		if !ok {
+			c.add(ChangeStatusRemoved, true, ptr, "response status %s removed", code)
+			continue
+		}
+		if ra == nil || rb == nil {
+			continue
+		}
+		diffHeaders(c, ptr+"/headers", ra.Headers, rb.Headers)
+		diffContent(c, ptr+"/content", ra.Content, rb.Content, false)
+	}
+	for code := range mb {
+		if _, ok := ma[code]; !ok {
+			c.add(ChangeStatusAdded, false, base+"/"+escape(code), "response status %s added", code)
+		}
+	}
+}
+
+// diffHeaders compares response headers by case-insensitive name. Clients
+// may rely on a required header, so losing one (or its guarantee) breaks.
+func diffHeaders(c *diffCtx, base string, a, b openapi3.Headers) {
+	ha, hb := headersMap(a), headersMap(b)
+	for key, ea := range ha {
+		eb, ok := hb[key]
+		if !ok {
+			c.add(ChangeRespHeaderRemoved, ea.h.Required, base+"/"+escape(ea.name), "response header %s removed", ea.name)
+			continue
+		}
+		ptr := base + "/" + escape(eb.name)
+		if ea.h.Required && !eb.h.Required {
+			c.add(ChangeRespHeaderBecameOpt, true, ptr, "response header %s became optional", eb.name)
+		}
+		diffSchema(c, ptr+"/schema", schemaOf(ea.h.Schema), schemaOf(eb.h.Schema), false)
+	}
+	for key, eb := range hb {
+		if _, ok := ha[key]; !ok {
+			c.add(ChangeRespHeaderAdded, false, base+"/"+escape(eb.name), "response header %s added", eb.name)
+		}
+	}
+}
+
+type headerAt struct {
+	name string
+	h    *openapi3.Header
+}
+
+func headersMap(hs openapi3.Headers) map[string]headerAt {
+	out := map[string]headerAt{}
+	for name, ref := range hs {
+		if ref != nil && ref.Value != nil {
+			out[strings.ToLower(name)] = headerAt{name, ref.Value}
+		}
+	}
+	return out
+}
+
+func responsesMap(rs *openapi3.Responses) map[string]*openapi3.Response {
+	out := map[string]*openapi3.Response{}
+	if rs == nil {
+		return out
+	}
+	for code, rr := range rs.Map() {
+		if rr == nil {
+			out[code] = nil
+			continue
+		}
+		out[code] = rr.Value
+	}
+	return out
+}
+
+// ---- media types ----
+
+func diffContent(c *diffCtx, base string, a, b openapi3.Content, request bool) {
+	removedID, addedID := ChangeRespMediaTypeRemoved, ChangeRespMediaTypeAdded
+	side := "response"
+	if request {
+		removedID, addedID = ChangeReqMediaTypeRemoved, ChangeReqMediaTypeAdded
+		side = "request"
+	}
+	for mt, ma := range a {
+		ptr := base + "/" + escape(mt)
+		mb, ok := b[mt]
+		if !ok {
+			c.add(removedID, true, ptr, "%s media type %s removed", side, mt)
+			continue
+		}
+		if ma == nil || mb == nil {
+			continue
+		}
+		diffSchema(c, ptr+"/schema", schemaOf(ma.Schema), schemaOf(mb.Schema), request)
+	}
+	for mt := range b {
+		if _, ok := a[mt]; !ok {
+			c.add(addedID, false, base+"/"+escape(mt), "%s media type %s added", side, mt)
+		}
+	}
+}
+
+// ---- schemas ----
+
+// diffSchema classifies schema changes. Requests break when the server accepts
+// less than before; responses break when the server may send something clients
+// did not expect before. Composed schemas are compared merged (see merged).
+func diffSchema(c *diffCtx, ptr string, a, b *openapi3.Schema, request bool) {
+	if a == nil || b == nil {
+		return
+	}
+	key := [2]*openapi3.Schema{a, b}
+	if c.seen[key] {
+		return // recursive schema already compared on this path
+	}
+	c.seen[key] = true
+	defer delete(c.seen, key)
+	a, b = merged(a), merged(b)
+
+	ta, tb := typesOf(a), typesOf(b)
+	if ta != "" && tb != "" && ta != tb {
+		if request {
+			c.add(ChangeReqTypeChanged, true, ptr+"/type", "request type changed from %s to %s", ta, tb)
+		} else {
+			c.add(ChangeRespTypeChanged, true, ptr+"/type", "response type changed from %s to %s", ta, tb)
+		}
+		return
+	}
+
+	if request && a.Nullable && !b.Nullable {
+		c.add(ChangeReqNullableRemoved, true, ptr+"/nullable", "request value no longer nullable")
+	}
+	if !request && !a.Nullable && b.Nullable {
+		c.add(ChangeRespNullableAdded, true, ptr+"/nullable", "response value became nullable")
+	}
+
+	diffEnum(c, ptr+"/enum", a.Enum, b.Enum, request)
+
+	// properties
+	reqA, reqB := toStrSet(a.Required), toStrSet(b.Required)
+	for name, pa := range a.Properties {
+		pptr := ptr + "/properties/" + escape(name)
+		pb, ok := b.Properties[name]
+		if !ok {
+			if request {
+				c.add(ChangeReqPropertyRemoved, false, pptr, "request property %q removed", name)
+			} else {
+				c.add(ChangeRespPropertyRemoved, true, pptr, "response property %q removed", name)
+			}
+			continue
+		}
+		switch {
+		case request && !reqA[name] && reqB[name]:
+			c.add(ChangeReqPropertyBecameReq, true, pptr, "request property %q became required", name)
+		case request && reqA[name] && !reqB[name]:
+			c.add(ChangeReqPropertyBecameOpt, false, pptr, "request property %q became optional", name)
+		case !request && reqA[name] && !reqB[name]:
+			c.add(ChangeRespPropertyBecameOpt, true, pptr, "response property %q became optional", name)
+		}
+		diffSchema(c, pptr, schemaOf(pa), schemaOf(pb), request)
+	}
+	for name := range b.Properties {
+		if _, ok := a.Properties[name]; ok {
+			continue
+		}
+		pptr := ptr + "/properties/" + escape(name)
+		switch {
+		case request && reqB[name]:
+			c.add(ChangeReqPropertyRequiredAdded, true, pptr, "required request property %q added", name)
+		case request:
+			c.add(ChangeReqPropertyAdded, false, pptr, "optional request property %q added", name)
+		default:
+			c.add(ChangeRespPropertyAdded, false, pptr, "response property %q added", name)
+		}
+	}
+
+	diffSchema(c, ptr+"/items", schemaOf(a.Items), schemaOf(b.Items), request)
+
+	// additionalProperties: only meaningful for objects
+	openA, openB := additionalAllowed(a), additionalAllowed(b)
+	switch {
+	case request && openA && !openB:
+		c.add(ChangeReqAdditionalPropsClosed, true, ptr+"/additionalProperties", "request no longer accepts additional properties")
+	case !request && !openA && openB:
+		c.add(ChangeRespAdditionalPropsOpen, false, ptr+"/additionalProperties", "response may contain additional properties")
+	}
+	diffSchema(c, ptr+"/additionalProperties", schemaOf(a.AdditionalProperties.Schema), schemaOf(b.AdditionalProperties.Schema), request)
+}
+
+// merged folds allOf/oneOf/anyOf into one schema: the union of properties
+// (as flatten does for drift), required from s and its allOf parts only, and
+// the first type, enum, items and additionalProperties found. Alternatives
+// of different types give a union type.
+func merged(s *openapi3.Schema) *openapi3.Schema {
+	if len(s.AllOf)+len(s.OneOf)+len(s.AnyOf) == 0 {
+		return s
+	}
+	m := *s
+	m.Properties = openapi3.Schemas{}
+	m.AllOf, m.OneOf, m.AnyOf = nil, nil, nil
+	for _, p := range flatten(s) {
+		for k, ref := range p.Properties {
+			if _, ok := m.Properties[k]; !ok {
+				m.Properties[k] = ref
+			}
+		}
+		if len(m.Enum) == 0 {
+			m.Enum = p.Enum
+		}
+		if m.Items == nil {
+			m.Items = p.Items
+		}
+		if m.AdditionalProperties.Has == nil && m.AdditionalProperties.Schema == nil {
+			m.AdditionalProperties = p.AdditionalProperties
+		}
+		m.Nullable = m.Nullable || p.Nullable
+	}
+	req := append([]string(nil), s.Required...)
+	for _, ref := range s.AllOf {
+		if ref != nil && ref.Value != nil {
+			req = append(req, merged(ref.Value).Required...)
+		}
+	}
+	m.Required = req
+	if m.Type == nil {
+		m.Type = composedType(s)
+	}
+	return &m
+}
+
+func composedType(s *openapi3.Schema) *openapi3.Types {
+	for _, ref := range s.AllOf {
+		if ref != nil && ref.Value != nil {
+			if t := merged(ref.Value).Type; t != nil && len(t.Slice()) > 0 {
+				return t
+			}
+		}
+	}
+	seen := map[string]bool{}
+	var ts openapi3.Types
+	for _, group := range []openapi3.SchemaRefs{s.OneOf, s.AnyOf} {
+		for _, ref := range group {
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			t := merged(ref.Value).Type
+			if t == nil || len(t.Slice()) == 0 {
+				return nil // an untyped alternative accepts anything
+			}
+			for _, x := range t.Slice() {
+				if !seen[x] {
+					seen[x] = true
+					ts = append(ts, x)
+				}
+			}
+		}
+	}
+	if len(ts) == 0 {
+		return nil
+	}
+	return &ts
+}
+
+// additionalAllowed reports whether an object schema accepts undeclared keys.
+func additionalAllowed(s *openapi3.Schema) bool {
+	ap := s.AdditionalProperties
+	return ap.Has == nil || *ap.Has || ap.Schema != nil
+}
+
+func diffEnum(c *diffCtx, ptr string, a, b []any, request bool) {
+	if len(a) == 0 && len(b) == 0 {
+		return
+	}
+	sa, sb := enumSet(a), enumSet(b)
+	var removed, added []string
+	for v := range sa {
+		if !sb[v] {
+			removed = append(removed, v)
+		}
+	}
+	for v := range sb {
+		if !sa[v] {
+			added = append(added, v)
+		}
+	}
+	sort.Strings(removed)
+	sort.Strings(added)
+
+	// An empty enum means "anything"; going to/from it widens/narrows fully.
+	if len(removed) > 0 || (len(a) == 0 && len(b) > 0) {
+		msg := "enum narrowed"
+		if len(removed) > 0 {
+			msg += ": removed " + strings.Join(removed, ", ")
+		}
+		if request {
+			c.add(ChangeReqEnumNarrowed, true, ptr, "request %s", msg)
+		} else {
+			c.add(ChangeRespEnumNarrowed, false, ptr, "response %s", msg)
+		}
+	}
+	if len(added) > 0 || (len(a) > 0 && len(b) == 0) {
+		msg := "enum widened"
+		if len(added) > 0 {
+			msg += ": added " + strings.Join(added, ", ")
+		}
+		if request {
+			c.add(ChangeReqEnumWidened, false, ptr, "request %s", msg)
+		} else {
+			c.add(ChangeRespEnumWidened, true, ptr, "response %s", msg)
+		}
+	}
+}
+
+// ---- security ----
+
+func securityOf(doc *openapi3.T, o *openapi3.Operation) openapi3.SecurityRequirements {
+	if o.Security != nil {
+		return *o.Security
+	}
+	return doc.Security
+}
+
+// diffSecurity compares the alternative requirement sets by scheme names.
+// No security at all is the anonymous alternative. Dropping an alternative
+// breaks clients that used it; adding one only offers a new way in.
+func diffSecurity(c *diffCtx, ptr string, a, b openapi3.SecurityRequirements) {
+	sa, sb := secSet(a), secSet(b)
+	for k := range sa {
+		if !sb[k] {
+			c.add(ChangeSecurityRemoved, true, ptr, "security alternative %s removed", secLabel(k))
+		}
+	}
+	for k := range sb {
+		if !sa[k] {
+			c.add(ChangeSecurityAdded, false, ptr, "security alternative %s added", secLabel(k))
+		}
+	}
+}
+
+func secSet(rs openapi3.SecurityRequirements) map[string]bool {
+	out := map[string]bool{}
+	if len(rs) == 0 {
+		out[""] = true
+	}
+	for _, r := range rs {
+		names := make([]string, 0, len(r))
+		for n := range r {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		out[strings.Join(names, "+")] = true
+	}
+	return out
+}
+
+func secLabel(k string) string {
+	if k == "" {
+		return "anonymous"
+	}
+	return k
+}
+
+// ---- small helpers ----
+
+func operation(doc *openapi3.T, op OpSig) *openapi3.Operation {
+	if doc == nil || doc.Paths == nil {
+		return nil
+	}
+	pi := doc.Paths.Value(op.Path)
+	if pi == nil {
+		return nil
+	}
+	return pi.GetOperation(op.Method)
+}
+
+func opPointer(op OpSig) string {
+	return "/paths/" + escape(op.Path) + "/" + strings.ToLower(op.Method)
+}
+
+// escape encodes a JSON pointer reference token (RFC 6901).
+func escape(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+// unescape decodes a reference token produced by escape.
+func unescape(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+}
+
+func schemaOf(r *openapi3.SchemaRef) *openapi3.Schema {
+	if r == nil {
+		return nil
+	}
+	return r.Value
+}
+
+func typesOf(s *openapi3.Schema) string {
+	if s.Type == nil {
+		return ""
+	}
+	ts := append([]string(nil), s.Type.Slice()...)
+	sort.Strings(ts)
+	return strings.Join(ts, "|")
+}
+
+func toStrSet(ss []string) map[string]bool {
+	m := make(map[string]bool, len(ss))
+	for _, s := range ss {
+		m[s] = true
+	}
+	return m
+}
+
+func enumSet(vs []any) map[string]bool {
+	m := make(map[string]bool, len(vs))
+	for _, v := range vs {
+		b, err := json.Marshal(v)
+		if err != nil {
+			m[fmt.Sprint(v)] = true
+			continue
+		}
+		m[string(b)] = true
+	}
+	return m
+}
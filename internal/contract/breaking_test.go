@@ -0,0 +1,286 @@
+package contract_test
+
+import (
+	"testing"
+
+	"sea-qa/internal/contract"
+)
+
+const deepA = `
+openapi: 3.0.3
+info: {title: A, version: "1"}
+security: [{ apiKey: [] }]
+paths:
+  /users:
+    get:
+      parameters:
+        - { name: limit, in: query, schema: { type: integer } }
+        - { name: cursor, in: query, schema: { type: string } }
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+                items: { $ref: "#/components/schemas/User" }
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [email]
+              properties:
+                email: { type: string }
+                role: { type: string, enum: [admin, member, guest] }
+                nickname: { type: string }
+          application/xml:
+            schema: { type: object }
+      responses:
+        "201": { description: created }
+components:
+  securitySchemes:
+    apiKey: { type: apiKey, in: header, name: X-Key }
+    oauth: { type: http, scheme: bearer }
+  schemas:
+    User:
+      type: object
+      required: [id, email]
+      properties:
+        id: { type: string }
+        email: { type: string }
+        status: { type: string, enum: [active, disabled] }
+        age: { type: integer }
+`
+
+const deepB = `
+openapi: 3.0.3
+info: {title: B, version: "1"}
+security: [{ oauth: [] }]
+paths:
+  /users:
+    get:
+      parameters:
+        - { name: limit, in: query, required: true, schema: { type: integer } }
+        - { name: tenant, in: header, required: true, schema: { type: string } }
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+                items: { $ref: "#/components/schemas/User" }
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [email, name]
+              properties:
+                email: { type: string }
+                name: { type: string }
+                role: { type: string, enum: [admin, member] }
+      responses:
+        "201": { description: created }
+components:
+  securitySchemes:
+    apiKey: { type: apiKey, in: header, name: X-Key }
+    oauth: { type: http, scheme: bearer }
+  schemas:
+    User:
+      type: object
+      required: [id]
+      properties:
+        id: { type: string }
+        email: { type: string }
+        status: { type: string, enum: [active, disabled, banned] }
+        age: { type: string }
+        createdAt: { type: string }
+`
+
+func TestDiff_ClassifiesBreakingChanges(t *testing.T) {
+	a, err := contract.LoadFromBytes([]byte(deepA))
+	if err != nil {
+		t.Fatalf("load A: %v", err)
+	}
+	b, err := contract.LoadFromBytes([]byte(deepB))
+	if err != nil {
+		t.Fatalf("load B: %v", err)
+	}
+	rep := contract.DiffDocs(a.Doc(), b.Doc())
+
+	want := []struct {
+		id       string
+		method   string
+		pointer  string
+		breaking bool
+	}{
+		{contract.ChangeParamBecameRequired, "GET", "/paths/~1users/get/parameters/0", true},
+		{contract.ChangeParamRemoved, "GET", "/paths/~1users/get/parameters/1", true},
+		{contract.ChangeParamRequiredAdded, "GET", "/paths/~1users/get/parameters/1", true},
+		{contract.ChangeRespPropertyBecameOpt, "GET", "/paths/~1users/get/responses/200/content/application~1json/schema/items/properties/email", true},
+		{contract.ChangeRespEnumWidened, "GET", "/paths/~1users/get/responses/200/content/application~1json/schema/items/properties/status/enum", true},
+		{contract.ChangeRespTypeChanged, "GET", "/paths/~1users/get/responses/200/content/application~1json/schema/items/properties/age/type", true},
+		{contract.ChangeRespPropertyAdded, "GET", "/paths/~1users/get/responses/200/content/application~1json/schema/items/properties/createdAt", false},
+		{contract.ChangeBodyBecameRequired, "POST", "/paths/~1users/post/requestBody", true},
+		{contract.ChangeReqMediaTypeRemoved, "POST", "/paths/~1users/post/requestBody/content/application~1xml", true},
+		{contract.ChangeReqPropertyRequiredAdded, "POST", "/paths/~1users/post/requestBody/content/application~1json/schema/properties/name", true},
+		{contract.ChangeReqPropertyRemoved, "POST", "/paths/~1users/post/requestBody/content/application~1json/schema/properties/nickname", false},
+		{contract.ChangeReqEnumNarrowed, "POST", "/paths/~1users/post/requestBody/content/application~1json/schema/properties/role/enum", true},
+		{contract.ChangeSecurityRemoved, "POST", "/paths/~1users/post/security", true},
+	}
+	for _, w := range want {
+		found := false
+		for _, c := range rep.Changes {
+			if c.ID == w.id && c.Method == w.method && c.Pointer == w.pointer {
+				found = true
+				if c.Breaking != w.breaking {
+					t.Errorf("%s at %s: breaking=%v, want %v", w.id, w.pointer, c.Breaking, w.breaking)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("missing change %s %s at %s", w.id, w.method, w.pointer)
+		}
+	}
+	if !rep.HasBreaking() {
+		t.Fatalf("expected breaking changes")
+	}
+	if rep.Breaking == len(rep.Changes) {
+		t.Fatalf("expected some non-breaking changes too: %+v", rep.Changes)
+	}
+}
+
+func TestDiff_IdenticalDocsHaveNoChanges(t *testing.T) {
+	a, err := contract.LoadFromBytes([]byte(deepA))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	rep := contract.DiffDocs(a.Doc(), a.Doc())
+	if len(rep.Changes) != 0 || rep.HasBreaking() {
+		t.Fatalf("expected no changes, got %+v", rep.Changes)
+	}
+}
+
+const composedA = `
+openapi: 3.0.3
+info: {title: A, version: "1"}
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: ok
+          headers:
+            X-Rate-Limit: { required: true, schema: { type: integer } }
+            X-Trace: { schema: { type: string } }
+          content:
+            application/json:
+              schema:
+                allOf:
+                  - $ref: "#/components/schemas/Base"
+                  - $ref: "#/components/schemas/Tagged"
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              allOf:
+                - $ref: "#/components/schemas/Base"
+      responses:
+        "201": { description: created }
+components:
+  schemas:
+    Base:
+      type: object
+      required: [id]
+      properties:
+        id: { type: integer }
+    Tagged:
+      type: object
+      properties:
+        tag: { type: string }
+`
+
+const composedB = `
+openapi: 3.0.3
+info: {title: B, version: "1"}
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: ok
+          headers:
+            x-rate-limit: { schema: { type: integer } }
+          content:
+            application/json:
+              schema:
+                allOf:
+                  - $ref: "#/components/schemas/Base"
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              allOf:
+                - $ref: "#/components/schemas/Base"
+                - additionalProperties: false
+      responses:
+        "201": { description: created }
+components:
+  schemas:
+    Base:
+      type: object
+      required: [id]
+      properties:
+        id: { type: string }
+`
+
+func TestDiff_ComposedSchemasAndHeaders(t *testing.T) {
+	a, err := contract.LoadFromBytes([]byte(composedA))
+	if err != nil {
+		t.Fatalf("load A: %v", err)
+	}
+	b, err := contract.LoadFromBytes([]byte(composedB))
+	if err != nil {
+		t.Fatalf("load B: %v", err)
+	}
+	rep := contract.DiffDocs(a.Doc(), b.Doc())
+
+	const schema = "/paths/~1pets/get/responses/200/content/application~1json/schema"
+	want := []struct {
+		id       string
+		method   string
+		pointer  string
+		breaking bool
+	}{
+		{contract.ChangeRespPropertyRemoved, "GET", schema + "/properties/tag", true},
+		{contract.ChangeRespTypeChanged, "GET", schema + "/properties/id/type", true},
+		{contract.ChangeRespHeaderBecameOpt, "GET", "/paths/~1pets/get/responses/200/headers/x-rate-limit", true},
+		{contract.ChangeRespHeaderRemoved, "GET", "/paths/~1pets/get/responses/200/headers/X-Trace", false},
+		{contract.ChangeReqTypeChanged, "POST", "/paths/~1pets/post/requestBody/content/application~1json/schema/properties/id/type", true},
+		{contract.ChangeReqAdditionalPropsClosed, "POST", "/paths/~1pets/post/requestBody/content/application~1json/schema/additionalProperties", true},
+	}
+	for _, w := range want {
+		found := false
+		for _, c := range rep.Changes {
+			if c.ID == w.id && c.Method == w.method && c.Pointer == w.pointer {
+				found = true
+				if c.Breaking != w.breaking {
+					t.Errorf("%s at %s: breaking=%v, want %v", w.id, w.pointer, c.Breaking, w.breaking)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("missing change %s %s at %s in %+v", w.id, w.method, w.pointer, rep.Changes)
+		}
+	}
+	if len(rep.Changes) != len(want) {
+		t.Errorf("got %d changes, want %d: %+v", len(rep.Changes), len(want), rep.Changes)
+	}
+}
@@ -26,11 +26,17 @@ type StatusChange struct {
 }
 
 type DiffReport struct {
-	Added         []OpSig        `json:"added"`          // present in B, not in A
-	Removed       []OpSig        `json:"removed"`        // present in A, not in B
-	ChangedStatus []StatusChange `json:"changed_status"` // same op, different status sets
+	Added         []OpSig        `json:"added"`             // present in B, not in A
+	Removed       []OpSig        `json:"removed"`           // present in A, not in B
+	ChangedStatus []StatusChange `json:"changed_status"`    // same op, different status sets
+	Changes       []Change       `json:"changes"`           // every classified change, incl. the above
+	Ignored       []Change       `json:"ignored,omitempty"` // changes accepted via ApplyIgnores
+	Breaking      int            `json:"breaking"`          // number of breaking entries in Changes
 }
 
+// HasBreaking reports whether any change is classified as breaking.
+func (r DiffReport) HasBreaking() bool { return r.Breaking > 0 }
+
 func DiffDocs(a, b *openapi3.T) DiffReport {
 	opsA := listOps(a)
 	opsB := listOps(b)
@@ -71,10 +77,20 @@ func DiffDocs(a, b *openapi3.T) DiffReport {
 	sortOps(removed)
 	sortChanges(changed)
 
+	changes := deepChanges(a, b, added, removed)
+	breaking := 0
+	for _, c := range changes {
+		if c.Breaking {
+			breaking++
+		}
+	}
+
 	return DiffReport{
 		Added:         added,
 		Removed:       removed,
 		ChangedStatus: changed,
+		Changes:       changes,
+		Breaking:      breaking,
 	}
 }
 
@@ -2,7 +2,10 @@ package contract_test
 
 import (
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"sea-qa/internal/contract"
 )
@@ -87,3 +90,60 @@ func toCSV(ss []string) string {
 	}
 	return out
 }
+
+func TestDiff_ApplyIgnores(t *testing.T) {
+	a, _ := contract.LoadFromBytes([]byte(specA))
+	b, _ := contract.LoadFromBytes([]byte(specB))
+	rep := contract.DiffDocs(a.Doc(), b.Doc())
+	if !rep.HasBreaking() {
+		t.Fatalf("expected breaking changes before ignores")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ignore.yaml")
+	ignore := `
+ignore:
+  - id: operation-removed
+    operation: GET /health
+    reason: replaced by /status
+    expires: 2099-01-01
+  - operation: POST /users
+    reason: status fix, clients already handle 200
+  - id: response-status-added
+    expires: 2000-01-01
+`
+	if err := os.WriteFile(path, []byte(ignore), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err := contract.LoadIgnoreFile(path)
+	if err != nil {
+		t.Fatalf("LoadIgnoreFile: %v", err)
+	}
+
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	expired := rep.ApplyIgnores(rules, now)
+	if len(expired) != 1 || expired[0].ID != "response-status-added" {
+		t.Fatalf("expired = %+v", expired)
+	}
+	if rep.HasBreaking() {
+		t.Fatalf("all breaking changes should be ignored, left: %+v", rep.Changes)
+	}
+	for _, c := range rep.Changes {
+		if c.Method == "POST" && c.Path == "/users" {
+			t.Fatalf("POST /users change should be ignored: %+v", c)
+		}
+	}
+	if len(rep.Ignored) == 0 {
+		t.Fatalf("expected ignored changes")
+	}
+}
+
+func TestLoadIgnoreFile_RequiresSelector(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ignore.yaml")
+	if err := os.WriteFile(path, []byte("ignore:\n  - reason: nothing to match\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := contract.LoadIgnoreFile(path); err == nil {
+		t.Fatalf("expected error for rule without id/operation")
+	}
+}
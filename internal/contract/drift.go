@@ -0,0 +1,266 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Drift kinds: what was on the wire but not in the spec.
+const (
+	DriftProperty = "property"
+	DriftHeader   = "header"
+	DriftStatus   = "status"
+)
+
+// DriftFinding is one undocumented element of a single response.
+type DriftFinding struct {
+	Kind   string `json:"kind"`
+	Status string `json:"status"` // response status the finding belongs to
+	Name   string `json:"name"`   // $.json.path, header name or status code
+}
+
+// transportHeaders are set by servers and proxies for every response and are
+// not expected to be documented.
+var transportHeaders = map[string]bool{
+	"Content-Type": true, "Content-Length": true, "Content-Encoding": true,
+	"Date": true, "Server": true, "Connection": true, "Keep-Alive": true,
+	"Transfer-Encoding": true, "Vary": true, "Cache-Control": true,
+	"Expires": true, "Pragma": true, "Etag": true, "Last-Modified": true,
+	"Age": true, "Via": true, "Alt-Svc": true, "Strict-Transport-Security": true,
+	"X-Content-Type-Options": true, "X-Frame-Options": true, "Set-Cookie": true,
+}
+
+// Drift lists the status code, headers and JSON properties of a response
+// that the spec does not describe. Validation ignores them (additional
+// properties are allowed by default), so they rot silently. Returns the
+// matched route like ValidateResponse.
+func (v *Validator) Drift(method, rawURL string, status int, header map[string][]string, body []byte) (routePath, routeMethod string, findings []DriftFinding, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("parse url: %w", err)
+	}
+	route, _, err := v.router.FindRoute(&http.Request{Method: method, URL: u, Header: http.Header(header)})
+	if err != nil {
+		return "", "", nil, fmt.Errorf("route not found: %w", err)
+	}
+	if route.Operation == nil || route.Operation.Responses == nil {
+		return route.Path, route.Method, nil, nil
+	}
+
+	code := strconv.Itoa(status)
+	ref := route.Operation.Responses.Status(status)
+	if ref == nil {
+		findings = append(findings, DriftFinding{Kind: DriftStatus, Status: code, Name: code})
+		ref = route.Operation.Responses.Default()
+	}
+	if ref == nil || ref.Value == nil {
+		return route.Path, route.Method, findings, nil
+	}
+	resp := ref.Value
+
+	var names []string
+	for k := range header {
+		names = append(names, http.CanonicalHeaderKey(k))
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		if transportHeaders[k] || strings.HasPrefix(k, "Access-Control-") || documentedHeader(resp.Headers, k) {
+			continue
+		}
+		findings = append(findings, DriftFinding{Kind: DriftHeader, Status: code, Name: k})
+	}
+
+	ct := http.Header(header).Get("Content-Type")
+	mt, _, _ := mime.ParseMediaType(ct)
+	if len(body) == 0 || (mt != "application/json" && !strings.HasSuffix(mt, "+json")) {
+		return route.Path, route.Method, findings, nil
+	}
+	media := resp.Content.Get(mt)
+	if media == nil || media.Schema == nil || media.Schema.Value == nil {
+		return route.Path, route.Method, findings, nil
+	}
+	var doc any
+	if json.Unmarshal(body, &doc) != nil {
+		return route.Path, route.Method, findings, nil
+	}
+	seen := map[string]bool{}
+	walkDrift(media.Schema.Value, doc, "$", func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			findings = append(findings, DriftFinding{Kind: DriftProperty, Status: code, Name: path})
+		}
+	})
+	return route.Path, route.Method, findings, nil
+}
+
+func documentedHeader(hs openapi3.Headers, name string) bool {
+	for k := range hs {
+		if strings.EqualFold(k, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// walkDrift reports object keys the schema does not declare. Composed
+// schemas (allOf/oneOf/anyOf) contribute the union of their properties;
+// objects without any declared properties are free-form and not reported.
+func walkDrift(s *openapi3.Schema, v any, path string, report func(string)) {
+	switch x := v.(type) {
+	case map[string]any:
+		parts := flatten(s)
+		props := map[string]*openapi3.Schema{}
+		var extra *openapi3.Schema
+		open := false
+		for _, p := range parts {
+			for k, ref := range p.Properties {
+				if ref != nil && ref.Value != nil && props[k] == nil {
+					props[k] = ref.Value
+				}
+			}
+			ap := p.AdditionalProperties
+			if ap.Schema != nil && ap.Schema.Value != nil {
+				extra = ap.Schema.Value
+			}
+			if ap.Has != nil && *ap.Has {
+				open = true
+			}
+		}
+		if len(props) == 0 && extra == nil {
+			return // free-form object
+		}
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := path + "." + k
+			switch {
+			case props[k] != nil:
+				walkDrift(props[k], x[k], child, report)
+			case extra != nil:
+				walkDrift(extra, x[k], path+".*", report)
+			case !open:
+				report(child)
+			}
+		}
+	case []any:
+		for _, p := range flatten(s) {
+			if p.Items != nil && p.Items.Value != nil {
+				for _, e := range x {
+					walkDrift(p.Items.Value, e, path+"[]", report)
+				}
+				return
+			}
+		}
+	}
+}
+
+// flatten returns s and every schema composed into it.
+func flatten(s *openapi3.Schema) []*openapi3.Schema {
+	out := []*openapi3.Schema{s}
+	for _, group := range []openapi3.SchemaRefs{s.AllOf, s.OneOf, s.AnyOf} {
+		for _, ref := range group {
+			if ref != nil && ref.Value != nil {
+				out = append(out, flatten(ref.Value)...)
+			}
+		}
+	}
+	return out
+}
+
+// ---- Aggregation ----
+
+type DriftItem struct {
+	Kind   string `json:"kind"`
+	Status string `json:"status"`
+	Name   string `json:"name"`
+	Count  int    `json:"count"` // responses it appeared in
+}
+
+// OperationDrift aggregates findings for one spec operation.
+type OperationDrift struct {
+	Spec      string      `json:"spec,omitempty"`
+	Method    string      `json:"method"`
+	Path      string      `json:"path"`
+	Responses int         `json:"responses"` // responses checked
+	Items     []DriftItem `json:"items"`
+}
+
+// DriftLog collects findings across a run; safe for concurrent use.
+type DriftLog struct {
+	mu  sync.Mutex
+	ops map[string]*driftOp
+}
+
+type driftOp struct {
+	spec, method, path string
+	responses          int
+	counts             map[DriftFinding]int
+}
+
+func NewDriftLog() *DriftLog { return &DriftLog{ops: map[string]*driftOp{}} }
+
+func (l *DriftLog) Add(spec, method, path string, findings []DriftFinding) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	key := spec + "\x00" + method + " " + path
+	op := l.ops[key]
+	if op == nil {
+		op = &driftOp{spec: spec, method: method, path: path, counts: map[DriftFinding]int{}}
+		l.ops[key] = op
+	}
+	op.responses++
+	for _, f := range findings {
+		op.counts[f]++
+	}
+}
+
+// Report returns the operations with at least one finding, sorted by spec,
+// path and method.
+func (l *DriftLog) Report() []OperationDrift {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var out []OperationDrift
+	for _, op := range l.ops {
+		if len(op.counts) == 0 {
+			continue
+		}
+		od := OperationDrift{Spec: op.spec, Method: op.method, Path: op.path, Responses: op.responses}
+		for f, n := range op.counts {
+			od.Items = append(od.Items, DriftItem{Kind: f.Kind, Status: f.Status, Name: f.Name, Count: n})
+		}
+		sort.Slice(od.Items, func(i, j int) bool {
+			a, b := od.Items[i], od.Items[j]
+			if a.Kind != b.Kind {
+				return a.Kind > b.Kind // status, property, header
+			}
+			if a.Status != b.Status {
+				return a.Status < b.Status
+			}
+			return a.Name < b.Name
+		})
+		out = append(out, od)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Spec != b.Spec {
+			return a.Spec < b.Spec
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+	return out
+}
@@ -0,0 +1,122 @@
+package contract_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sea-qa/internal/contract"
+	"sea-qa/internal/executor"
+	"sea-qa/internal/ir"
+)
+
+const driftSpec = `
+openapi: 3.0.3
+info: { title: Drift, version: "1" }
+paths:
+  /orders/{id}:
+    get:
+      parameters:
+        - { name: id, in: path, required: true, schema: { type: integer } }
+      responses:
+        "200":
+          description: ok
+          headers:
+            X-Rate-Limit: { schema: { type: integer } }
+          content:
+            application/json:
+              schema:
+                allOf:
+                  - { $ref: "#/components/schemas/Base" }
+                  - type: object
+                    properties:
+                      lines:
+                        type: array
+                        items: { type: object, properties: { sku: { type: string } } }
+                      meta: { type: object }
+                      labels: { type: object, additionalProperties: { type: object, properties: { v: { type: string } } } }
+        default:
+          description: error
+          content:
+            application/json:
+              schema: { type: object, properties: { message: { type: string } } }
+components:
+  schemas:
+    Base:
+      type: object
+      properties:
+        id: { type: integer }
+`
+
+func TestDrift_ReportsUndocumentedElements(t *testing.T) {
+	v, err := contract.LoadFromBytes([]byte(driftSpec))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	hdr := map[string][]string{
+		"Content-Type":  {"application/json"},
+		"Date":          {"now"},
+		"X-Rate-Limit":  {"10"},
+		"X-Debug-Trace": {"abc"},
+	}
+	body := `{"id":1,"internal":true,"lines":[{"sku":"a","cost":2},{"sku":"b","cost":3}],"meta":{"any":1},"labels":{"x":{"v":"1","w":2}}}`
+	path, method, got, err := v.Drift("GET", "http://h/orders/1", 200, hdr, []byte(body))
+	if err != nil {
+		t.Fatalf("drift: %v", err)
+	}
+	if path != "/orders/{id}" || method != "GET" {
+		t.Fatalf("route = %s %s", method, path)
+	}
+	var names []string
+	for _, f := range got {
+		names = append(names, f.Kind+":"+f.Name)
+	}
+	want := "header:X-Debug-Trace,property:$.internal,property:$.labels.*.w,property:$.lines[].cost"
+	if s := strings.Join(names, ","); s != want {
+		t.Fatalf("findings = %s\nwant       %s", s, want)
+	}
+
+	_, _, got, err = v.Drift("GET", "http://h/orders/1", 418, map[string][]string{"Content-Type": {"application/json"}}, []byte(`{"message":"x","code":7}`))
+	if err != nil {
+		t.Fatalf("drift: %v", err)
+	}
+	if len(got) != 2 || got[0].Kind != contract.DriftStatus || got[0].Name != "418" || got[1].Name != "$.code" {
+		t.Fatalf("418 findings = %+v", got)
+	}
+}
+
+func TestDrift_AggregatedPerOperationInRun(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/orders/2" {
+			_, _ = w.Write([]byte(`{"id":2}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":1,"internal":true}`))
+	}))
+	defer srv.Close()
+	v, err := contract.LoadFromBytes([]byte(driftSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	step := func(id string) ir.Step {
+		return ir.Step{
+			Request: ir.Request{Method: "GET", URL: srv.URL + "/orders/" + id},
+			Expect:  []ir.Expectation{{Type: ir.ExpectContract}},
+		}
+	}
+	suite := &ir.TestSuite{Name: "d", Scenarios: []ir.Scenario{{Name: "s", Steps: []ir.Step{step("1"), step("2"), step("3")}}}}
+	res, err := executor.New().WithContract(v).RunSuite(context.Background(), suite)
+	if err != nil || !res.Passed {
+		t.Fatalf("run: %v %+v", err, res)
+	}
+	if len(res.Drift) != 1 {
+		t.Fatalf("drift = %+v", res.Drift)
+	}
+	op := res.Drift[0]
+	if op.Path != "/orders/{id}" || op.Responses != 3 || len(op.Items) != 1 || op.Items[0].Name != "$.internal" || op.Items[0].Count != 2 {
+		t.Fatalf("operation drift = %+v", op)
+	}
+}
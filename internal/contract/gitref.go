@@ -0,0 +1,84 @@
+package contract
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// GitRefPrefix marks a spec reference read from the local git repository:
+// git:<rev>:<path>, e.g. git:origin/main:api/openapi.yaml. The path is
+// relative to the current directory, like a plain file path.
+const GitRefPrefix = "git:"
+
+// LoadFromRef loads a spec from a file path or a git:<rev>:<path> reference.
+// Relative external $refs inside a git spec are read from the same revision.
+func LoadFromRef(ref string) (*Validator, error) {
+	if !strings.HasPrefix(ref, GitRefPrefix) {
+		return LoadFromFile(ref)
+	}
+	rev, p, err := parseGitRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := git("rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", ref, err)
+	}
+	full := path.Clean(strings.TrimSpace(string(prefix)) + filepath.ToSlash(p))
+
+	data, err := gitShow(rev, full)
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", ref, err)
+	}
+	readRef := func(_ *openapi3.Loader, u *url.URL) ([]byte, error) {
+		if u.Scheme != "" || u.Host != "" {
+			return openapi3.DefaultReadFromURI(nil, u)
+		}
+		return gitShow(rev, strings.TrimPrefix(path.Clean(u.Path), "/"))
+	}
+	doc, version, err := loadDoc(data, &url.URL{Path: full}, readRef)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ref, err)
+	}
+	return build(doc, version)
+}
+
+func parseGitRef(ref string) (rev, p string, err error) {
+	rest := strings.TrimPrefix(ref, GitRefPrefix)
+	// git forbids ':' in ref names, so the first one separates rev from path
+	rev, p, ok := strings.Cut(rest, ":")
+	if !ok || rev == "" || p == "" {
+		return "", "", fmt.Errorf("bad git reference %q (want git:<rev>:<path>)", ref)
+	}
+	// git would take it as an option, e.g. --output=<file>
+	if strings.HasPrefix(rev, "-") {
+		return "", "", fmt.Errorf("bad git reference %q: revision must not start with '-'", ref)
+	}
+	return rev, p, nil
+}
+
+func gitShow(rev, repoPath string) ([]byte, error) {
+	return git("show", rev+":"+repoPath)
+}
+
+func git(args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, fmt.Errorf("git %s: %s", strings.Join(args, " "), msg)
+	}
+	return stdout.Bytes(), nil
+}
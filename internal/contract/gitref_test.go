@@ -0,0 +1,95 @@
+package contract_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sea-qa/internal/contract"
+)
+
+func TestLoadFromRef_Git(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com",
+			"GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(rel, body string) {
+		t.Helper()
+		p := filepath.Join(repo, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	write("api/openapi.yaml", `
+openapi: 3.0.3
+info: {title: A, version: "1"}
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: { $ref: "schemas/user.yaml" }
+`)
+	write("api/schemas/user.yaml", "type: object\nproperties:\n  id: { type: string }\n")
+	run("add", "-A")
+	run("commit", "-q", "-m", "v1")
+	run("tag", "v1")
+
+	// working tree moves on; the tagged revision must still be readable
+	write("api/openapi.yaml", "openapi: 3.0.3\ninfo: {title: B, version: \"2\"}\npaths: {}\n")
+	run("commit", "-q", "-am", "v2")
+
+	t.Chdir(filepath.Join(repo, "api"))
+
+	old, err := contract.LoadFromRef("git:v1:openapi.yaml")
+	if err != nil {
+		t.Fatalf("LoadFromRef v1: %v", err)
+	}
+	cur, err := contract.LoadFromRef("openapi.yaml")
+	if err != nil {
+		t.Fatalf("LoadFromRef file: %v", err)
+	}
+	rep := contract.DiffDocs(old.Doc(), cur.Doc())
+	if len(rep.Removed) != 1 || rep.Removed[0].Path != "/users" {
+		t.Fatalf("removed = %+v", rep.Removed)
+	}
+	sch := old.Doc().Paths.Value("/users").Get.Responses.Value("200").Value.Content.Get("application/json").Schema.Value
+	if sch == nil || sch.Properties["id"] == nil {
+		t.Fatalf("external $ref not resolved from git revision")
+	}
+
+	if _, err := contract.LoadFromRef("git:v1"); err == nil {
+		t.Fatalf("expected malformed ref error")
+	}
+	if _, err := contract.LoadFromRef("git:nope:openapi.yaml"); err == nil {
+		t.Fatalf("expected unknown revision error")
+	}
+	out := filepath.Join(t.TempDir(), "x")
+	if _, err := contract.LoadFromRef("git:--output=" + out + ":openapi.yaml"); err == nil || !strings.Contains(err.Error(), "must not start with '-'") {
+		t.Fatalf("option-like revision: err = %v", err)
+	}
+	if _, err := os.Stat(out); err == nil {
+		t.Fatalf("git wrote %s", out)
+	}
+}
@@ -0,0 +1,117 @@
+package contract
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IgnoreRule accepts a known change so it no longer counts for --fail-on.
+// A rule matches when every non-empty field matches; at least one of ID or
+// Operation must be set. Rules past Expires stop matching.
+type IgnoreRule struct {
+	ID        string `json:"id,omitempty" yaml:"id,omitempty"`               // change id, e.g. response-property-removed
+	Operation string `json:"operation,omitempty" yaml:"operation,omitempty"` // "METHOD /path"
+	Pointer   string `json:"pointer,omitempty" yaml:"pointer,omitempty"`
+	Reason    string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Expires   string `json:"expires,omitempty" yaml:"expires,omitempty"` // YYYY-MM-DD, inclusive
+}
+
+type ignoreFile struct {
+	Ignore []IgnoreRule `yaml:"ignore"`
+}
+
+// LoadIgnoreFile reads a YAML (or JSON) file of the form `ignore: [rules...]`.
+func LoadIgnoreFile(path string) ([]IgnoreRule, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read ignore file: %w", err)
+	}
+	var f ignoreFile
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("parse ignore file: %w", err)
+	}
+	for i, r := range f.Ignore {
+		if r.ID == "" && r.Operation == "" {
+			return nil, fmt.Errorf("ignore[%d]: id or operation is required", i)
+		}
+		if r.Expires != "" {
+			if _, err := time.Parse(time.DateOnly, r.Expires); err != nil {
+				return nil, fmt.Errorf("ignore[%d]: bad expires %q (want YYYY-MM-DD)", i, r.Expires)
+			}
+		}
+	}
+	return f.Ignore, nil
+}
+
+func (r IgnoreRule) expired(now time.Time) bool {
+	if r.Expires == "" {
+		return false
+	}
+	t, err := time.Parse(time.DateOnly, r.Expires)
+	if err != nil {
+		return true
+	}
+	return now.After(t.AddDate(0, 0, 1))
+}
+
+func (r IgnoreRule) matches(c Change) bool {
+	if r.ID != "" && r.ID != c.ID {
+		return false
+	}
+	if r.Operation != "" {
+		method, path, _ := strings.Cut(strings.TrimSpace(r.Operation), " ")
+		if !strings.EqualFold(method, c.Method) || strings.TrimSpace(path) != c.Path {
+			return false
+		}
+	}
+	if r.Pointer != "" && r.Pointer != c.Pointer {
+		return false
+	}
+	return true
+}
+
+// ApplyIgnores moves changes matched by an active rule from Changes to
+// Ignored and recounts Breaking. It returns the rules that have expired so
+// callers can warn about them.
+func (rep *DiffReport) ApplyIgnores(rules []IgnoreRule, now time.Time) []IgnoreRule {
+	var active, expired []IgnoreRule
+	for _, r := range rules {
+		if r.expired(now) {
+			expired = append(expired, r)
+		} else {
+			active = append(active, r)
+		}
+	}
+
+	kept := rep.Changes[:0]
+	for _, c := range rep.Changes {
+		ignored := false
+		for _, r := range active {
+			if r.matches(c) {
+				ignored = true
+				break
+			}
+		}
+		if ignored {
+			rep.Ignored = append(rep.Ignored, c)
+		} else {
+			kept = append(kept, c)
+		}
+	}
+	rep.Changes = kept
+
+	rep.Breaking = 0
+	for _, c := range rep.Changes {
+		if c.Breaking {
+			rep.Breaking++
+		}
+	}
+	return expired
+}
@@ -0,0 +1,473 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/invopop/yaml"
+)
+
+// Spec versions reported by Validator.Version().
+const (
+	VersionSwagger2 = "2.0"
+	Version30       = "3.0"
+	Version31       = "3.1"
+)
+
+// loadDoc detects the document version and returns an OpenAPI 3.0 model.
+// Swagger 2.0 is converted with openapi2conv; 3.1 is down-converted to the
+// 3.0 dialect kin-openapi understands (type arrays → nullable, const → enum, ...).
+// location may be nil; when set, relative external $refs resolve against it
+// and are read with readRef (nil reads from the filesystem / network).
+func loadDoc(data []byte, location *url.URL, readRef openapi3.ReadFromURIFunc) (*openapi3.T, string, error) {
+	raw, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, "", fmt.Errorf("decode: %w", err)
+	}
+	var head struct {
+		Swagger string `json:"swagger"`
+		OpenAPI string `json:"openapi"`
+	}
+	if err := json.Unmarshal(raw, &head); err != nil {
+		return nil, "", fmt.Errorf("decode: %w", err)
+	}
+
+	loader := &openapi3.Loader{IsExternalRefsAllowed: true, ReadFromURIFunc: readRef}
+
+	switch {
+	case strings.HasPrefix(head.Swagger, "2."):
+		var doc2 openapi2.T
+		if err := json.Unmarshal(raw, &doc2); err != nil {
+			return nil, "", fmt.Errorf("decode swagger 2.0: %w", err)
+		}
+		doc, err := openapi2conv.ToV3WithLoader(&doc2, loader, location)
+		if err != nil {
+			return nil, "", fmt.Errorf("convert swagger 2.0: %w", err)
+		}
+		return doc, VersionSwagger2, nil
+
+	case strings.HasPrefix(head.OpenAPI, "3.1"):
+		var m map[string]any
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return nil, "", fmt.Errorf("decode: %w", err)
+		}
+		if err := downgrade31(m); err != nil {
+			return nil, "", fmt.Errorf("convert 3.1: %w", err)
+		}
+		b, err := json.Marshal(m)
+		if err != nil {
+			return nil, "", fmt.Errorf("convert 3.1: %w", err)
+		}
+		doc, err := loadData(loader, b, location)
+		if err != nil {
+			return nil, "", err
+		}
+		return doc, Version31, nil
+
+	case strings.HasPrefix(head.OpenAPI, "3.0"):
+		doc, err := loadData(loader, data, location)
+		if err != nil {
+			return nil, "", err
+		}
+		return doc, Version30, nil
+
+	case head.Swagger != "":
+		return nil, "", fmt.Errorf("unsupported swagger version %q", head.Swagger)
+	case head.OpenAPI != "":
+		return nil, "", fmt.Errorf("unsupported openapi version %q", head.OpenAPI)
+	default:
+		return nil, "", fmt.Errorf("missing 'openapi' or 'swagger' version field")
+	}
+}
+
+func loadData(loader *openapi3.Loader, data []byte, location *url.URL) (*openapi3.T, error) {
+	var (
+		doc *openapi3.T
+		err error
+	)
+	if location != nil {
+		doc, err = loader.LoadFromDataWithPath(data, location)
+	} else {
+		doc, err = loader.LoadFromData(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load: %w", err)
+	}
+	return doc, nil
+}
+
+func fileLocation(path string) *url.URL {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	return &url.URL{Path: filepath.ToSlash(abs)}
+}
+
+// ---- 3.1 → 3.0 down-conversion ----
+
+// downgrade31 rewrites a decoded 3.1 document in place so that it loads as
+// 3.0.3. Only constructs with a faithful 3.0 equivalent are translated;
+// JSON Schema keywords 3.0 has no notion of are dropped. $defs schemas are
+// moved to components.schemas and path item refs are inlined; refs that
+// still have no 3.0 target are an error.
+func downgrade31(doc map[string]any) error {
+	doc["openapi"] = "3.0.3"
+	delete(doc, "webhooks")
+	delete(doc, "jsonSchemaDialect")
+	if err := inlinePathItems(doc); err != nil {
+		return err
+	}
+	hoistDefs(doc)
+	if _, ok := doc["paths"]; !ok {
+		doc["paths"] = map[string]any{}
+	}
+	if info, ok := doc["info"].(map[string]any); ok {
+		delete(info, "summary")
+		if lic, ok := info["license"].(map[string]any); ok {
+			delete(lic, "identifier")
+		}
+	}
+
+	if comps, ok := doc["components"].(map[string]any); ok {
+		eachValue(comps["schemas"], downgradeSchema)
+		eachValue(comps["parameters"], downgradeParam)
+		eachValue(comps["headers"], downgradeParam)
+		eachValue(comps["requestBodies"], downgradeContent)
+		eachValue(comps["responses"], downgradeResponse)
+	}
+
+	eachValue(doc["paths"], func(item any) {
+		pi, ok := item.(map[string]any)
+		if !ok {
+			return
+		}
+		eachElem(pi["parameters"], downgradeParam)
+		for _, m := range []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"} {
+			op, ok := pi[m].(map[string]any)
+			if !ok {
+				continue
+			}
+			eachElem(op["parameters"], downgradeParam)
+			downgradeContent(op["requestBody"])
+			eachValue(op["responses"], downgradeResponse)
+		}
+	})
+	return nil
+}
+
+// ---- 3.1 refs without a 3.0 target ----
+
+const pathItemsRef = "#/components/pathItems/"
+
+// inlinePathItems replaces paths that $ref components.pathItems with a copy
+// of the target, then drops components.pathItems.
+func inlinePathItems(doc map[string]any) error {
+	comps, _ := doc["components"].(map[string]any)
+	items, _ := comps["pathItems"].(map[string]any)
+	paths, _ := doc["paths"].(map[string]any)
+	for p, v := range paths {
+		pi, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		for depth := 0; ; depth++ {
+			ref, ok := pi["$ref"].(string)
+			if !ok || !strings.HasPrefix(ref, pathItemsRef) {
+				break
+			}
+			target, ok := items[unescape(strings.TrimPrefix(ref, pathItemsRef))].(map[string]any)
+			if !ok || depth == 10 {
+				return fmt.Errorf("unresolved path item $ref %q", ref)
+			}
+			// siblings of the $ref (summary, description, ...) win
+			merged := cloneJSON(target).(map[string]any)
+			for k, x := range pi {
+				if k != "$ref" {
+					merged[k] = x
+				}
+			}
+			pi = merged
+		}
+		paths[p] = pi
+	}
+	delete(comps, "pathItems")
+
+	var bad string
+	walkRefs(doc, func(ref string) string {
+		if bad == "" && strings.HasPrefix(ref, pathItemsRef) {
+			bad = ref
+		}
+		return ref
+	})
+	if bad != "" {
+		return fmt.Errorf("unsupported 3.1 construct: $ref %q into components/pathItems", bad)
+	}
+	return nil
+}
+
+// hoistDefs moves every $defs schema into components.schemas (renamed on
+// a clash) and rewrites the refs pointing at it.
+func hoistDefs(doc map[string]any) {
+	type def struct {
+		ptr, name string
+		schema    any
+	}
+	var defs []def
+	var walk func(v any, ptr string)
+	walk = func(v any, ptr string) {
+		switch x := v.(type) {
+		case map[string]any:
+			if ds, ok := x["$defs"].(map[string]any); ok {
+				delete(x, "$defs")
+				for name, schema := range ds {
+					p := ptr + "/$defs/" + escape(name)
+					defs = append(defs, def{p, name, schema})
+					walk(schema, p)
+				}
+			}
+			for k, e := range x {
+				walk(e, ptr+"/"+escape(k))
+			}
+		case []any:
+			for i, e := range x {
+				walk(e, ptr+"/"+strconv.Itoa(i))
+			}
+		}
+	}
+	walk(doc, "#")
+	if len(defs) == 0 {
+		return
+	}
+
+	comps, ok := doc["components"].(map[string]any)
+	if !ok {
+		comps = map[string]any{}
+		doc["components"] = comps
+	}
+	schemas, ok := comps["schemas"].(map[string]any)
+	if !ok {
+		schemas = map[string]any{}
+		comps["schemas"] = schemas
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].ptr < defs[j].ptr })
+	moved := map[string]string{}
+	for _, d := range defs {
+		name := d.name
+		for n := 2; schemas[name] != nil; n++ {
+			name = d.name + "_" + strconv.Itoa(n)
+		}
+		schemas[name] = d.schema
+		moved[d.ptr] = "#/components/schemas/" + escape(name)
+	}
+
+	walkRefs(doc, func(ref string) string {
+		// longest match, so refs into nested $defs go to their own schema
+		best := ""
+		for old := range moved {
+			if (ref == old || strings.HasPrefix(ref, old+"/")) && len(old) > len(best) {
+				best = old
+			}
+		}
+		if best == "" {
+			return ref
+		}
+		return moved[best] + strings.TrimPrefix(ref, best)
+	})
+}
+
+// walkRefs replaces every "$ref" string in v with fn(ref).
+func walkRefs(v any, fn func(string) string) {
+	switch x := v.(type) {
+	case map[string]any:
+		for k, e := range x {
+			if ref, ok := e.(string); ok && k == "$ref" {
+				x[k] = fn(ref)
+				continue
+			}
+			walkRefs(e, fn)
+		}
+	case []any:
+		for _, e := range x {
+			walkRefs(e, fn)
+		}
+	}
+}
+
+func cloneJSON(v any) any {
+	b, _ := json.Marshal(v)
+	var out any
+	_ = json.Unmarshal(b, &out)
+	return out
+}
+
+func downgradeParam(v any) {
+	p, ok := v.(map[string]any)
+	if !ok {
+		return
+	}
+	downgradeSchema(p["schema"])
+	downgradeContent(p)
+}
+
+func downgradeResponse(v any) {
+	r, ok := v.(map[string]any)
+	if !ok {
+		return
+	}
+	eachValue(r["headers"], downgradeParam)
+	downgradeContent(r)
+}
+
+// downgradeContent handles anything carrying a "content" media type map.
+func downgradeContent(v any) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return
+	}
+	eachValue(m["content"], func(mt any) {
+		if mm, ok := mt.(map[string]any); ok {
+			downgradeSchema(mm["schema"])
+		}
+	})
+}
+
+var dropped31Keywords = []string{
+	"$schema", "$id", "$anchor", "$comment", "$defs", "$dynamicRef", "$dynamicAnchor",
+	"contentMediaType", "contentEncoding", "contentSchema",
+	"unevaluatedProperties", "unevaluatedItems", "prefixItems",
+	"dependentRequired", "dependentSchemas", "patternProperties", "propertyNames",
+	"if", "then", "else", "contains", "minContains", "maxContains",
+}
+
+func downgradeSchema(v any) {
+	s, ok := v.(map[string]any)
+	if !ok {
+		return
+	}
+	// 3.0 ignores $ref siblings; kin-openapi rejects some of them.
+	if _, ok := s["$ref"]; ok {
+		for k := range s {
+			if k != "$ref" {
+				delete(s, k)
+			}
+		}
+		return
+	}
+
+	for _, k := range dropped31Keywords {
+		delete(s, k)
+	}
+
+	// type: [string, null] → type: string, nullable: true
+	if ts, ok := s["type"].([]any); ok {
+		var types []string
+		for _, t := range ts {
+			if str, ok := t.(string); ok {
+				if str == "null" {
+					s["nullable"] = true
+					continue
+				}
+				types = append(types, str)
+			}
+		}
+		switch len(types) {
+		case 0:
+			delete(s, "type")
+		case 1:
+			s["type"] = types[0]
+		default:
+			// no 3.0 equivalent for a union of types; express as anyOf
+			delete(s, "type")
+			alts := make([]any, 0, len(types))
+			for _, t := range types {
+				alts = append(alts, map[string]any{"type": t})
+			}
+			s["anyOf"] = alts
+		}
+	} else if t, ok := s["type"].(string); ok && t == "null" {
+		delete(s, "type")
+		s["nullable"] = true
+	}
+
+	// anyOf/oneOf: [X, {type: null}] → X + nullable
+	for _, key := range []string{"anyOf", "oneOf"} {
+		alts, ok := s[key].([]any)
+		if !ok {
+			continue
+		}
+		kept := alts[:0]
+		for _, a := range alts {
+			if am, ok := a.(map[string]any); ok && len(am) == 1 && am["type"] == "null" {
+				s["nullable"] = true
+				continue
+			}
+			kept = append(kept, a)
+		}
+		if len(kept) == 0 {
+			delete(s, key)
+		} else {
+			s[key] = kept
+		}
+	}
+
+	if c, ok := s["const"]; ok {
+		s["enum"] = []any{c}
+		delete(s, "const")
+	}
+	if ex, ok := s["examples"].([]any); ok {
+		if _, has := s["example"]; !has && len(ex) > 0 {
+			s["example"] = ex[0]
+		}
+		delete(s, "examples")
+	}
+	// 3.1 bounds are numbers and may sit next to an inclusive one; 3.0 has
+	// a single bound with a flag, so keep whichever is stricter
+	for _, b := range []struct {
+		excl, incl string
+		sign       float64
+	}{{"exclusiveMinimum", "minimum", 1}, {"exclusiveMaximum", "maximum", -1}} {
+		n, ok := s[b.excl].(float64)
+		if !ok {
+			continue
+		}
+		if m, has := s[b.incl].(float64); has && (m-n)*b.sign > 0 {
+			delete(s, b.excl)
+			continue
+		}
+		s[b.incl] = n
+		s[b.excl] = true
+	}
+
+	eachValue(s["properties"], downgradeSchema)
+	downgradeSchema(s["items"])
+	downgradeSchema(s["additionalProperties"])
+	downgradeSchema(s["not"])
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		eachElem(s[key], downgradeSchema)
+	}
+}
+
+func eachValue(v any, fn func(any)) {
+	if m, ok := v.(map[string]any); ok {
+		for _, x := range m {
+			fn(x)
+		}
+	}
+}
+
+func eachElem(v any, fn func(any)) {
+	if a, ok := v.([]any); ok {
+		for _, x := range a {
+			fn(x)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package contract
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Match selects which requests a spec applies to. Empty fields match anything;
+// a Match with every field empty is the suite's default spec.
+type Match struct {
+	Host        string // exact host (with port, if any), case-insensitive
+	BaseURL     string // URL prefix; may contain ${VARS}, expanded per request
+	Prefix      string // path prefix, e.g. /billing
+	StripPrefix bool   // drop Prefix from the path before routing within the spec
+}
+
+type Route struct {
+	Name      string
+	Match     Match
+	Validator *Validator
+}
+
+// Registry routes requests across several specs (one per microservice).
+type Registry struct {
+	routes []*Route
+}
+
+func NewRegistry() *Registry { return &Registry{} }
+
+func (r *Registry) Add(name string, m Match, v *Validator) {
+	r.routes = append(r.routes, &Route{Name: name, Match: m, Validator: v})
+}
+
+func (r *Registry) Routes() []*Route { return r.routes }
+
+func (r *Registry) Len() int { return len(r.routes) }
+
+// Resolve returns the most specific route for rawURL and the URL to validate
+// against it (with Prefix removed when StripPrefix is set). expand resolves
+// ${VARS} in BaseURL; it may be nil.
+func (r *Registry) Resolve(rawURL string, expand func(string) string) (*Route, string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, "", false
+	}
+
+	var (
+		best      *Route
+		bestScore = -1
+	)
+	for _, rt := range r.routes {
+		score, ok := rt.Match.score(rawURL, u, expand)
+		if ok && score > bestScore {
+			best, bestScore = rt, score
+		}
+	}
+	if best == nil {
+		return nil, "", false
+	}
+	if best.Match.StripPrefix && best.Match.Prefix != "" {
+		cp := *u
+		cp.Path = "/" + strings.TrimLeft(strings.TrimPrefix(u.Path, strings.TrimRight(best.Match.Prefix, "/")), "/")
+		cp.RawPath = ""
+		return best, cp.String(), true
+	}
+	return best, rawURL, true
+}
+
+// score reports whether the request matches and how specific the match is;
+// longer base URLs / prefixes win, any explicit matcher beats the default.
+func (m Match) score(rawURL string, u *url.URL, expand func(string) string) (int, bool) {
+	score := 0
+	if m.BaseURL != "" {
+		base := m.BaseURL
+		if expand != nil {
+			base = expand(base)
+		}
+		if strings.Contains(base, "${") {
+			return 0, false
+		}
+		if !hasPathPrefix(rawURL, strings.TrimRight(base, "/")) {
+			return 0, false
+		}
+		score += 1000 + len(base)
+	}
+	if m.Host != "" {
+		if !strings.EqualFold(u.Host, m.Host) && !strings.EqualFold(u.Hostname(), m.Host) {
+			return 0, false
+		}
+		score += 100
+	}
+	if m.Prefix != "" {
+		if !hasPathPrefix(u.Path, strings.TrimRight(m.Prefix, "/")) {
+			return 0, false
+		}
+		score += 10 + len(m.Prefix)
+	}
+	return score, true
+}
+
+// hasPathPrefix is a prefix check that respects segment boundaries:
+// /users matches /users and /users/1 but not /usersettings.
+func hasPathPrefix(s, prefix string) bool {
+	if !strings.HasPrefix(s, prefix) {
+		return false
+	}
+	rest := s[len(prefix):]
+	return rest == "" || rest[0] == '/' || rest[0] == '?' || rest[0] == '#'
+}
@@ -0,0 +1,134 @@
+package contract_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sea-qa/internal/contract"
+	"sea-qa/internal/executor"
+	"sea-qa/internal/ir"
+)
+
+const usersSpec = `
+openapi: 3.0.3
+info: { title: users, version: "1" }
+paths:
+  /users/{id}:
+    get:
+      parameters: [{ name: id, in: path, required: true, schema: { type: string } }]
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: { type: object, required: [id], properties: { id: { type: string } } }
+`
+
+const billingSpec = `
+openapi: 3.0.3
+info: { title: billing, version: "1" }
+paths:
+  /invoices:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: { type: array, items: { type: object } }
+`
+
+func TestRegistry_Resolve(t *testing.T) {
+	users, _ := contract.LoadFromBytes([]byte(usersSpec))
+	billing, _ := contract.LoadFromBytes([]byte(billingSpec))
+
+	reg := contract.NewRegistry()
+	reg.Add("default", contract.Match{}, users)
+	reg.Add("users", contract.Match{BaseURL: "${USERS_URL}"}, users)
+	reg.Add("billing", contract.Match{Host: "gw.local", Prefix: "/billing", StripPrefix: true}, billing)
+
+	expand := func(s string) string {
+		if s == "${USERS_URL}" {
+			return "http://users.local:8080/api"
+		}
+		return s
+	}
+
+	cases := []struct {
+		url, spec, specURL string
+	}{
+		{"http://users.local:8080/api/users/1", "users", "http://users.local:8080/api/users/1"},
+		{"http://users.local:8080/apiv2/users/1", "default", "http://users.local:8080/apiv2/users/1"},
+		{"http://gw.local/billing/invoices?x=1", "billing", "http://gw.local/invoices?x=1"},
+		{"http://gw.local/billingx/invoices", "default", "http://gw.local/billingx/invoices"},
+	}
+	for _, c := range cases {
+		rt, u, ok := reg.Resolve(c.url, expand)
+		if !ok {
+			t.Fatalf("%s: no route", c.url)
+		}
+		if rt.Name != c.spec || u != c.specURL {
+			t.Errorf("%s: got %s %s, want %s %s", c.url, rt.Name, u, c.spec, c.specURL)
+		}
+	}
+}
+
+func TestRunner_RoutesContractsPerSpec(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "u-1"})
+	})
+	mux.HandleFunc("/billing/invoices", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"total": 10}]`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	users, err := contract.LoadFromBytes([]byte(usersSpec))
+	if err != nil {
+		t.Fatalf("load users: %v", err)
+	}
+	billing, err := contract.LoadFromBytes([]byte(billingSpec))
+	if err != nil {
+		t.Fatalf("load billing: %v", err)
+	}
+	reg := contract.NewRegistry()
+	reg.Add("users", contract.Match{Prefix: "/users"}, users)
+	reg.Add("billing", contract.Match{Prefix: "/billing", StripPrefix: true}, billing)
+
+	step := func(path string) ir.Step {
+		return ir.Step{
+			Request: ir.Request{Method: http.MethodGet, URL: srv.URL + path},
+			Expect:  []ir.Expectation{{Type: ir.ExpectContract, Value: true}},
+		}
+	}
+	suite := &ir.TestSuite{
+		Name: "multi",
+		Scenarios: []ir.Scenario{{
+			Name:  "both services",
+			Steps: []ir.Step{step("/users/u-1"), step("/billing/invoices")},
+		}},
+	}
+
+	r := executor.New().WithContracts(reg)
+	res, err := r.RunSuite(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("RunSuite: %v", err)
+	}
+	if !res.Passed {
+		t.Fatalf("suite should pass: %+v", res.Scenarios[0].Steps)
+	}
+
+	cov := r.CoveredBySpec()
+	if !cov["users"]["GET"]["/users/{id}"] {
+		t.Errorf("users coverage missing: %v", cov)
+	}
+	if !cov["billing"]["GET"]["/invoices"] {
+		t.Errorf("billing coverage missing: %v", cov)
+	}
+}
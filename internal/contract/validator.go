@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3filter"
@@ -15,29 +16,34 @@ import (
 )
 
 type Validator struct {
-	doc    *openapi3.T
-	router routers.Router
+	doc     *openapi3.T
+	router  routers.Router
+	version string
 }
 
+// LoadFromFile loads a Swagger 2.0, OpenAPI 3.0 or OpenAPI 3.1 document.
+// 2.0 and 3.1 are converted to 3.0 in memory; see Version().
 func LoadFromFile(path string) (*Validator, error) {
-	loader := &openapi3.Loader{IsExternalRefsAllowed: true}
-	doc, err := loader.LoadFromFile(path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("load: %w", err)
 	}
-	return build(doc)
+	doc, version, err := loadDoc(data, fileLocation(path), nil)
+	if err != nil {
+		return nil, err
+	}
+	return build(doc, version)
 }
 
 func LoadFromBytes(b []byte) (*Validator, error) {
-	loader := &openapi3.Loader{IsExternalRefsAllowed: true}
-	doc, err := loader.LoadFromData(b)
+	doc, version, err := loadDoc(b, nil, nil)
 	if err != nil {
-		return nil, fmt.Errorf("load: %w", err)
+		return nil, err
 	}
-	return build(doc)
+	return build(doc, version)
 }
 
-func build(doc *openapi3.T) (*Validator, error) {
+func build(doc *openapi3.T, version string) (*Validator, error) {
 	// Strict: if the spec is invalid, fail fast with a clear message.
 	if err := doc.Validate(context.Background()); err != nil {
 		return nil, fmt.Errorf("validate spec: %w", err)
@@ -46,11 +52,14 @@ func build(doc *openapi3.T) (*Validator, error) {
 	if err != nil {
 		return nil, fmt.Errorf("router: %w", err)
 	}
-	return &Validator{doc: doc, router: r}, nil
+	return &Validator{doc: doc, router: r, version: version}, nil
 }
 
 func (v *Validator) Doc() *openapi3.T { return v.doc }
 
+// Version reports the source dialect: VersionSwagger2, Version30 or Version31.
+func (v *Validator) Version() string { return v.version }
+
 // ValidateResponse validates (method, url, status, headers, body) against the spec.
 // Returns (templatedPath, method) for coverage accounting.
 func (v *Validator) ValidateResponse(
@@ -0,0 +1,246 @@
+package contract_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sea-qa/internal/contract"
+	"sea-qa/internal/reporter"
+)
+
+const swagger2JSON = `{
+  "swagger": "2.0",
+  "info": {"title": "Pets", "version": "1"},
+  "basePath": "/",
+  "produces": ["application/json"],
+  "paths": {
+    "/pets/{id}": {
+      "get": {
+        "parameters": [{"name": "id", "in": "path", "required": true, "type": "integer"}],
+        "responses": {
+          "200": {"description": "ok", "schema": {"$ref": "#/definitions/Pet"}},
+          "404": {"description": "missing"}
+        }
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "required": ["id", "name"],
+      "properties": {"id": {"type": "integer"}, "name": {"type": "string"}}
+    }
+  }
+}`
+
+const openapi31YAML = `
+openapi: 3.1.0
+info: { title: Pets, version: "1", summary: pets service }
+paths:
+  /pets/{id}:
+    get:
+      parameters:
+        - { name: id, in: path, required: true, schema: { type: integer } }
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: { $ref: "#/components/schemas/Pet" }
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [id, name, nickname]
+      properties:
+        id: { type: integer, exclusiveMinimum: 0 }
+        name: { type: string, examples: [Rex] }
+        nickname: { type: [string, "null"] }
+        kind: { const: dog }
+        age: { type: integer, minimum: 5, exclusiveMinimum: 3, maximum: 20, exclusiveMaximum: 10 }
+`
+
+func TestLoad_Swagger2(t *testing.T) {
+	v, err := contract.LoadFromBytes([]byte(swagger2JSON))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if v.Version() != contract.VersionSwagger2 {
+		t.Fatalf("version = %q, want %q", v.Version(), contract.VersionSwagger2)
+	}
+
+	hdr := map[string][]string{"Content-Type": {"application/json"}}
+	path, method, err := v.ValidateResponse(context.Background(), http.MethodGet, "http://x/pets/1", 200, hdr, []byte(`{"id":1,"name":"Rex"}`))
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if path != "/pets/{id}" || method != http.MethodGet {
+		t.Fatalf("route = %s %s", method, path)
+	}
+	if _, _, err := v.ValidateResponse(context.Background(), http.MethodGet, "http://x/pets/1", 200, hdr, []byte(`{"id":"one"}`)); err == nil {
+		t.Fatalf("expected schema violation for converted 2.0 definition")
+	}
+
+	rep := reporter.ComputeCoverage(v.Doc(), map[string]map[string]bool{"GET": {"/pets/{id}": true}})
+	if rep.Total != 1 || rep.Covered != 1 {
+		t.Fatalf("coverage = %+v", rep)
+	}
+}
+
+func TestLoad_OpenAPI31_NullableTypeArray(t *testing.T) {
+	v, err := contract.LoadFromBytes([]byte(openapi31YAML))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if v.Version() != contract.Version31 {
+		t.Fatalf("version = %q, want %q", v.Version(), contract.Version31)
+	}
+
+	hdr := map[string][]string{"Content-Type": {"application/json"}}
+	ok := []byte(`{"id":1,"name":"Rex","nickname":null,"kind":"dog","age":5}`)
+	if _, _, err := v.ValidateResponse(context.Background(), http.MethodGet, "http://x/pets/1", 200, hdr, ok); err != nil {
+		t.Fatalf("null nickname should validate: %v", err)
+	}
+
+	cases := map[string]string{
+		"wrong type":        `{"id":1,"name":"Rex","nickname":5}`,
+		"const mismatch":    `{"id":1,"name":"Rex","nickname":null,"kind":"cat"}`,
+		"exclusive minimum": `{"id":0,"name":"Rex","nickname":null}`,
+		"missing required":  `{"id":1,"nickname":null}`,
+		"stricter minimum":  `{"id":1,"name":"Rex","nickname":null,"age":4}`,
+		"exclusive maximum": `{"id":1,"name":"Rex","nickname":null,"age":10}`,
+	}
+	for name, body := range cases {
+		if _, _, err := v.ValidateResponse(context.Background(), http.MethodGet, "http://x/pets/1", 200, hdr, []byte(body)); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestLoad_DiffAcrossVersions(t *testing.T) {
+	dir := t.TempDir()
+	p2 := filepath.Join(dir, "v2.json")
+	p31 := filepath.Join(dir, "v31.yaml")
+	if err := os.WriteFile(p2, []byte(swagger2JSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p31, []byte(openapi31YAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	a, err := contract.LoadFromFile(p2)
+	if err != nil {
+		t.Fatalf("load A: %v", err)
+	}
+	b, err := contract.LoadFromFile(p31)
+	if err != nil {
+		t.Fatalf("load B: %v", err)
+	}
+
+	rep := contract.DiffDocs(a.Doc(), b.Doc())
+	if len(rep.Added) != 0 || len(rep.Removed) != 0 {
+		t.Fatalf("unexpected op changes: %+v", rep)
+	}
+	if len(rep.ChangedStatus) != 1 {
+		t.Fatalf("expected 404 removal to show as status change, got %+v", rep.ChangedStatus)
+	}
+}
+
+func TestLoad_UnknownVersion(t *testing.T) {
+	if _, err := contract.LoadFromBytes([]byte("openapi: 4.0.0\ninfo: {title: x, version: '1'}\n")); err == nil {
+		t.Fatalf("expected unsupported version error")
+	}
+}
+
+const openapi31Defs = `
+openapi: 3.1.0
+info: { title: Pets, version: "1" }
+paths:
+  /pets/{id}:
+    get:
+      parameters:
+        - { name: id, in: path, required: true, schema: { type: integer } }
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: { $ref: "#/components/schemas/Pet" }
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [id, owner]
+      properties:
+        id: { type: integer }
+        owner: { $ref: "#/components/schemas/Pet/$defs/Owner" }
+      $defs:
+        Owner:
+          type: object
+          required: [name]
+          properties:
+            name: { type: string }
+`
+
+func TestLoad_OpenAPI31_Defs(t *testing.T) {
+	v, err := contract.LoadFromBytes([]byte(openapi31Defs))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	hdr := map[string][]string{"Content-Type": {"application/json"}}
+	ok := []byte(`{"id":1,"owner":{"name":"ann"}}`)
+	if _, _, err := v.ValidateResponse(context.Background(), http.MethodGet, "http://x/pets/1", 200, hdr, ok); err != nil {
+		t.Fatalf("valid body: %v", err)
+	}
+	bad := []byte(`{"id":1,"owner":{"name":5}}`)
+	if _, _, err := v.ValidateResponse(context.Background(), http.MethodGet, "http://x/pets/1", 200, hdr, bad); err == nil {
+		t.Fatal("expected validation error from the $defs schema")
+	}
+}
+
+const openapi31PathItems = `
+openapi: 3.1.0
+info: { title: Pets, version: "1" }
+paths:
+  /pets/{id}:
+    $ref: "#/components/pathItems/Pet"
+components:
+  pathItems:
+    Pet:
+      get:
+        parameters:
+          - { name: id, in: path, required: true, schema: { type: integer } }
+        responses:
+          "200":
+            description: ok
+            content:
+              application/json:
+                schema: { type: object, required: [id] }
+`
+
+func TestLoad_OpenAPI31_PathItems(t *testing.T) {
+	v, err := contract.LoadFromBytes([]byte(openapi31PathItems))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	hdr := map[string][]string{"Content-Type": {"application/json"}}
+	if _, _, err := v.ValidateResponse(context.Background(), http.MethodGet, "http://x/pets/1", 200, hdr, []byte(`{"id":1}`)); err != nil {
+		t.Fatalf("valid body: %v", err)
+	}
+	if _, _, err := v.ValidateResponse(context.Background(), http.MethodGet, "http://x/pets/1", 200, hdr, []byte(`{}`)); err == nil {
+		t.Fatal("expected validation error from the inlined path item")
+	}
+
+	// A ref into a path item's operation has no 3.0 form.
+	deep := openapi31PathItems + `
+  responses:
+    Ok: { $ref: "#/components/pathItems/Pet/get/responses/200" }
+`
+	_, err = contract.LoadFromBytes([]byte(deep))
+	if err == nil || !strings.Contains(err.Error(), "unsupported 3.1 construct") {
+		t.Fatalf("err = %v, want unsupported 3.1 construct", err)
+	}
+}
@@ -0,0 +1,440 @@
+package curl
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"sea-qa/internal/capture"
+	"sea-qa/internal/ir"
+)
+
+// ---- Rendering ----
+
+// Command renders a request as a copy-pasteable, POSIX-shell-quoted curl
+// invocation, one option per line after the URL.
+func Command(method, rawURL string, headers map[string]string, body string) string {
+	method = strings.ToUpper(method)
+	head := "curl "
+	if method != "" && (method != "GET" || body != "") {
+		head += "-X " + method + " "
+	}
+	parts := []string{head + quote(rawURL)}
+
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts = append(parts, "-H "+quote(k+": "+headers[k]))
+	}
+	if body != "" {
+		parts = append(parts, "--data-raw "+quote(body))
+	}
+	return strings.Join(parts, " \\\n  ")
+}
+
+// quote wraps s in single quotes unless it is made only of shell-safe
+// characters.
+func quote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=@%+,") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// ---- Import ----
+
+type ImportOptions struct {
+	Name    string // suite name; defaults to "curl import"
+	BaseVar string // variable replacing the most frequent origin; defaults to BASE_URL
+}
+
+// Note records a curl option that was dropped or only partly translated.
+type Note struct {
+	Command int // 1-based position in the input
+	Reason  string
+}
+
+// ToSuite parses every curl command in text (separated by newlines, ';'
+// or '&&') into one scenario with a step per command.
+func ToSuite(text string, opt ImportOptions) (*ir.TestSuite, []Note, error) {
+	name := opt.Name
+	if name == "" {
+		name = "curl import"
+	}
+	baseVar := opt.BaseVar
+	if baseVar == "" {
+		baseVar = "BASE_URL"
+	}
+	cmds, err := split(text)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		reqs  []ir.Request
+		notes []Note
+	)
+	for i, args := range cmds {
+		req, why, err := Parse(args)
+		if err != nil {
+			return nil, notes, fmt.Errorf("command %d: %w", i+1, err)
+		}
+		for _, w := range why {
+			notes = append(notes, Note{Command: i + 1, Reason: w})
+		}
+		reqs = append(reqs, req)
+	}
+
+	origin := mainOrigin(reqs)
+	sc := ir.Scenario{Name: "curl session", Tags: []string{"curl"}}
+	for i, req := range reqs {
+		path := req.URL
+		if u, err := url.Parse(req.URL); err == nil {
+			path = u.RequestURI()
+		}
+		if origin != "" && strings.HasPrefix(req.URL, origin) {
+			req.URL = "${" + baseVar + "}" + strings.TrimPrefix(req.URL, origin)
+		}
+		sc.Steps = append(sc.Steps, ir.Step{
+			Name:    fmt.Sprintf("%d %s %s", i+1, req.Method, path),
+			Request: req,
+		})
+	}
+	return &ir.TestSuite{Name: name, Scenarios: []ir.Scenario{sc}}, notes, nil
+}
+
+// Parse translates one tokenized curl command (args[0] == "curl"). The
+// returned notes list options that were ignored.
+func Parse(args []string) (ir.Request, []string, error) {
+	var (
+		req    ir.Request
+		notes  []string
+		data   []string
+		get    bool
+		method string
+	)
+	args = append([]string(nil), args...) // --opt=value is split in place
+	header := func(k, v string) {
+		if req.Headers == nil {
+			req.Headers = map[string]string{}
+		}
+		if capture.Credential(k) && !strings.Contains(v, "${") {
+			v = capture.CredentialRef(k, v)
+			notes = append(notes, fmt.Sprintf("%s value replaced with %s", k, v))
+		}
+		req.Headers[k] = v
+	}
+	for i := 1; i < len(args); i++ {
+		a := args[i]
+		next := func() (string, error) {
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("%s needs a value", a)
+			}
+			i++
+			return args[i], nil
+		}
+		// --opt=value
+		if strings.HasPrefix(a, "--") {
+			if k, v, ok := strings.Cut(a, "="); ok {
+				a = k
+				args = append(args[:i+1], append([]string{v}, args[i+1:]...)...)
+			}
+		} else if len(a) > 2 && a[0] == '-' {
+			// -sSL, -XPOST, -H'Accept: x', -d@file
+			split := shortOpts(a)
+			a = split[0]
+			args = append(args[:i], append(split, args[i+1:]...)...)
+		}
+		switch a {
+		case "-X", "--request":
+			v, err := next()
+			if err != nil {
+				return req, notes, err
+			}
+			method = strings.ToUpper(v)
+		case "-H", "--header":
+			v, err := next()
+			if err != nil {
+				return req, notes, err
+			}
+			k, val, ok := strings.Cut(v, ":")
+			if !ok {
+				notes = append(notes, fmt.Sprintf("malformed header %q dropped", v))
+				continue
+			}
+			k = strings.TrimSpace(k)
+			if !capture.KeepHeader(k) {
+				if strings.EqualFold(k, "Cookie") {
+					notes = append(notes, "Cookie header dropped")
+				}
+				continue
+			}
+			header(k, strings.TrimSpace(val))
+		case "-d", "--data", "--data-raw", "--data-binary", "--data-ascii", "--data-urlencode":
+			v, err := next()
+			if err != nil {
+				return req, notes, err
+			}
+			if strings.HasPrefix(v, "@") && a != "--data-raw" {
+				notes = append(notes, fmt.Sprintf("body from file %s not inlined", v))
+				continue
+			}
+			if a == "--data-urlencode" {
+				switch k, val, ok := strings.Cut(v, "="); {
+				case ok && k != "":
+					v = k + "=" + url.QueryEscape(val)
+				case ok:
+					v = url.QueryEscape(val) // "=content" sends only the content
+				default:
+					v = url.QueryEscape(v)
+				}
+			}
+			data = append(data, v)
+		case "--json":
+			v, err := next()
+			if err != nil {
+				return req, notes, err
+			}
+			data = append(data, v)
+			header("Content-Type", "application/json")
+			header("Accept", "application/json")
+		case "-u", "--user":
+			v, err := next()
+			if err != nil {
+				return req, notes, err
+			}
+			header("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(v)))
+		case "-G", "--get":
+			get = true
+		case "--url":
+			v, err := next()
+			if err != nil {
+				return req, notes, err
+			}
+			req.URL = v
+		case "-F", "--form":
+			v, err := next()
+			if err != nil {
+				return req, notes, err
+			}
+			notes = append(notes, fmt.Sprintf("multipart form field %q not translated", v))
+		case "-b", "--cookie":
+			if _, err := next(); err != nil {
+				return req, notes, err
+			}
+			notes = append(notes, "cookies dropped")
+		case "-A", "--user-agent", "-e", "--referer", "-o", "--output", "-m", "--max-time",
+			"--connect-timeout", "-w", "--write-out", "--retry":
+			if _, err := next(); err != nil {
+				return req, notes, err
+			}
+		case "-s", "--silent", "-S", "--show-error", "-i", "--include", "-v", "--verbose",
+			"-L", "--location", "--compressed", "-f", "--fail", "-g", "--globoff", "-N", "--no-buffer":
+		case "-k", "--insecure":
+			notes = append(notes, "--insecure ignored; the runner verifies TLS")
+		default:
+			if strings.HasPrefix(a, "-") {
+				notes = append(notes, fmt.Sprintf("option %s ignored", a))
+				continue
+			}
+			if req.URL != "" {
+				return req, notes, fmt.Errorf("more than one URL (%q, %q)", req.URL, a)
+			}
+			req.URL = a
+		}
+	}
+	if req.URL == "" {
+		return req, notes, fmt.Errorf("no URL")
+	}
+	if !strings.Contains(req.URL, "://") {
+		req.URL = "http://" + req.URL
+	}
+
+	body := strings.Join(data, "&")
+	switch {
+	case get && body != "":
+		sep := "?"
+		if strings.Contains(req.URL, "?") {
+			sep = "&"
+		}
+		req.URL += sep + body
+	case body != "":
+		req.Body = capture.ParseBody(body)
+		if ct := contentType(req.Headers); ct == "" {
+			if _, ok := req.Body.(string); ok {
+				header("Content-Type", "application/x-www-form-urlencoded") // curl's default
+			} else {
+				header("Content-Type", "application/json")
+			}
+		}
+	}
+	switch {
+	case method != "":
+		req.Method = method
+	case body != "" && !get:
+		req.Method = "POST"
+	default:
+		req.Method = "GET"
+	}
+	return req, notes, nil
+}
+
+// shortValue lists the short options that take a value.
+const shortValue = "XHduFbAeomw"
+
+// shortOpts splits a cluster of short options into separate arguments. The
+// first option that takes a value ends the cluster; the rest is its value.
+func shortOpts(a string) []string {
+	var out []string
+	for j := 1; j < len(a); j++ {
+		out = append(out, "-"+a[j:j+1])
+		if strings.IndexByte(shortValue, a[j]) >= 0 {
+			if j+1 < len(a) {
+				out = append(out, a[j+1:])
+			}
+			break
+		}
+	}
+	return out
+}
+
+func contentType(h map[string]string) string {
+	for k, v := range h {
+		if strings.EqualFold(k, "Content-Type") {
+			return v
+		}
+	}
+	return ""
+}
+
+func mainOrigin(reqs []ir.Request) string {
+	count := map[string]int{}
+	for _, r := range reqs {
+		if u, err := url.Parse(r.URL); err == nil && u.Host != "" {
+			count[u.Scheme+"://"+u.Host]++
+		}
+	}
+	best := ""
+	for o, n := range count {
+		if n > count[best] || (n == count[best] && o < best) {
+			best = o
+		}
+	}
+	return best
+}
+
+// ---- Shell tokenizing ----
+
+// split tokenizes POSIX-shell-style text into commands. It understands
+// single, double and $'...' quotes, backslash escapes and line
+// continuations (including cmd.exe's ^); text before the first "curl"
+// of a command is ignored.
+func split(text string) ([][]string, error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "^\n", "") // Windows "Copy as cURL (cmd)"
+	var (
+		cmds [][]string
+		cur  []string
+		tok  strings.Builder
+		have bool // tok holds a (possibly empty) word
+	)
+	endWord := func() {
+		if have {
+			cur = append(cur, tok.String())
+		}
+		tok.Reset()
+		have = false
+	}
+	endCmd := func() {
+		endWord()
+		for len(cur) > 0 && cur[0] != "curl" {
+			cur = cur[1:]
+		}
+		if len(cur) > 0 {
+			cmds = append(cmds, cur)
+		}
+		cur = nil
+	}
+
+	rs := []rune(text)
+	for i := 0; i < len(rs); i++ {
+		c := rs[i]
+		switch {
+		case c == '\\':
+			if i+1 < len(rs) {
+				i++
+				if rs[i] != '\n' {
+					tok.WriteRune(rs[i])
+					have = true
+				}
+			}
+		case c == '\'':
+			j := i + 1
+			for j < len(rs) && rs[j] != '\'' {
+				j++
+			}
+			if j >= len(rs) {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			tok.WriteString(string(rs[i+1 : j]))
+			have, i = true, j
+		case c == '$' && i+1 < len(rs) && rs[i+1] == '\'':
+			j := i + 2
+			for ; j < len(rs) && rs[j] != '\''; j++ {
+				if rs[j] == '\\' && j+1 < len(rs) {
+					j++
+					switch rs[j] {
+					case 'n':
+						tok.WriteByte('\n')
+					case 't':
+						tok.WriteByte('\t')
+					case 'r':
+						tok.WriteByte('\r')
+					default:
+						tok.WriteRune(rs[j])
+					}
+					continue
+				}
+				tok.WriteRune(rs[j])
+			}
+			if j >= len(rs) {
+				return nil, fmt.Errorf("unterminated $' quote")
+			}
+			have, i = true, j
+		case c == '"':
+			j := i + 1
+			for ; j < len(rs) && rs[j] != '"'; j++ {
+				if rs[j] == '\\' && j+1 < len(rs) && strings.ContainsRune("\"\\$`\n", rs[j+1]) {
+					j++
+					if rs[j] == '\n' {
+						continue
+					}
+				}
+				tok.WriteRune(rs[j])
+			}
+			if j >= len(rs) {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+			have, i = true, j
+		case c == '\n' || c == ';':
+			endCmd()
+		case c == '&' && i+1 < len(rs) && rs[i+1] == '&':
+			endCmd()
+			i++
+		case c == ' ' || c == '\t':
+			endWord()
+		default:
+			tok.WriteRune(c)
+			have = true
+		}
+	}
+	endCmd()
+	if len(cmds) == 0 {
+		return nil, fmt.Errorf("no curl command found")
+	}
+	return cmds, nil
+}
@@ -0,0 +1,123 @@
+package curl_test
+
+import (
+	"strings"
+	"testing"
+
+	"sea-qa/internal/curl"
+	"sea-qa/internal/parser"
+)
+
+const pasted = `# from the browser
+curl 'https://api.example.com/users?page=2' \
+  -H 'accept: application/json' \
+  -H 'cookie: session=secret' \
+  -H 'sec-fetch-mode: cors' \
+  -H 'authorization: Bearer abc' \
+  --compressed
+curl -X PATCH "https://api.example.com/users/7" -H "Content-Type: application/json" \
+  --data-raw $'{"name":"O\'Brien"}'
+curl -u admin:pw -d 'a=1' -d 'b=two words' https://other.example.com/form && curl --json '{"x":1}' https://api.example.com/x -k`
+
+func TestImport_PastedCommands(t *testing.T) {
+	suite, notes, err := curl.ToSuite(pasted, curl.ImportOptions{})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	data, err := parser.Marshal(suite)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	parsed, err := parser.New().ParseBytes(data)
+	if err != nil {
+		t.Fatalf("parse:\n%s\n%v", data, err)
+	}
+	steps := parsed.Scenarios[0].Steps
+	if len(steps) != 4 {
+		t.Fatalf("steps = %d:\n%s", len(steps), data)
+	}
+
+	for _, secret := range []string{"Bearer abc", "YWRtaW46cHc="} {
+		if strings.Contains(string(data), secret) {
+			t.Fatalf("suite contains %q:\n%s", secret, data)
+		}
+	}
+
+	get := steps[0].Request
+	if get.Method != "GET" || get.URL != "${BASE_URL}/users?page=2" {
+		t.Fatalf("get = %s %s", get.Method, get.URL)
+	}
+	if get.Headers["authorization"] != "Bearer ${TOKEN}" || len(get.Headers) != 2 {
+		t.Fatalf("get headers = %v", get.Headers)
+	}
+
+	patch := steps[1].Request
+	if body, ok := patch.Body.(map[string]any); patch.Method != "PATCH" || !ok || body["name"] != "O'Brien" {
+		t.Fatalf("patch = %s %#v", patch.Method, patch.Body)
+	}
+
+	form := steps[2].Request
+	if form.Method != "POST" || form.URL != "https://other.example.com/form" || form.Body != "a=1&b=two words" {
+		t.Fatalf("form = %s %s %#v", form.Method, form.URL, form.Body)
+	}
+	if form.Headers["Authorization"] != "Basic ${TOKEN}" || form.Headers["Content-Type"] != "application/x-www-form-urlencoded" {
+		t.Fatalf("form headers = %v", form.Headers)
+	}
+
+	if js := steps[3].Request; js.Method != "POST" || js.Headers["Content-Type"] != "application/json" {
+		t.Fatalf("json = %+v", js)
+	}
+
+	var got []string
+	for _, n := range notes {
+		got = append(got, n.Reason)
+	}
+	if s := strings.Join(got, "; "); !strings.Contains(s, "Cookie header dropped") || !strings.Contains(s, "--insecure") ||
+		!strings.Contains(s, "Authorization value replaced with Basic ${TOKEN}") {
+		t.Fatalf("notes = %s", s)
+	}
+}
+
+func TestCommand_RoundTrips(t *testing.T) {
+	headers := map[string]string{"Content-Type": "application/json", "X-Note": "it's fine"}
+	body := `{"q":"a b","n":1}`
+	cmd := curl.Command("post", "https://api.example.com/search?x=1&y=2", headers, body)
+	if !strings.HasPrefix(cmd, "curl -X POST 'https://api.example.com/search?x=1&y=2' \\\n") {
+		t.Fatalf("command:\n%s", cmd)
+	}
+
+	suite, _, err := curl.ToSuite(cmd, curl.ImportOptions{BaseVar: "API"})
+	if err != nil {
+		t.Fatalf("re-import:\n%s\n%v", cmd, err)
+	}
+	req := suite.Scenarios[0].Steps[0].Request
+	if req.Method != "POST" || req.URL != "${API}/search?x=1&y=2" {
+		t.Fatalf("request = %s %s", req.Method, req.URL)
+	}
+	if req.Headers["X-Note"] != "it's fine" || req.Headers["Content-Type"] != "application/json" {
+		t.Fatalf("headers = %v", req.Headers)
+	}
+	if b, ok := req.Body.(map[string]any); !ok || b["q"] != "a b" {
+		t.Fatalf("body = %#v", req.Body)
+	}
+
+	if got := curl.Command("GET", "http://x/y", nil, ""); got != "curl http://x/y" {
+		t.Fatalf("plain GET = %q", got)
+	}
+}
+
+func TestParse_AttachedShortOptions(t *testing.T) {
+	req, notes, err := curl.Parse([]string{"curl", "-sSXPUT", "-HAccept: text/plain", "-d@body.json", "-dname=x", "--data-urlencode", "=a b", "https://x/y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Method != "PUT" || req.Headers["Accept"] != "text/plain" {
+		t.Fatalf("request = %+v", req)
+	}
+	if req.Body != "name=x&a+b" {
+		t.Fatalf("body = %#v", req.Body)
+	}
+	if len(notes) != 1 || !strings.Contains(notes[0], "body from file @body.json") {
+		t.Fatalf("notes = %v", notes)
+	}
+}
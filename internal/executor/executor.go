@@ -7,27 +7,41 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"os"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
+	"sea-qa/internal/assertion"
 	"sea-qa/internal/contract"
 	"sea-qa/internal/hooks"
 	"sea-qa/internal/ir"
+	"sea-qa/internal/redact"
 )
 
 // ---- Results model ----
 
 type SuiteResult struct {
-	Passed     bool
-	Scenarios  []ScenarioResult
-	DurationMs float64
+	Passed      bool
+	Errors      []string       `json:",omitempty"` // beforeSuite/afterSuite hook failures
+	HookResults []hooks.Result `json:",omitempty"` // beforeSuite/afterSuite invocations
+	Scenarios   []ScenarioResult
+	DurationMs  float64
+	Drift       []contract.OperationDrift `json:",omitempty"` // undocumented response elements, per operation
 }
 
 type ScenarioResult struct {
 	Name        string
 	Passed      bool
+	Errors      []string       `json:",omitempty"` // beforeScenario/afterScenario hook failures
+	HookResults []hooks.Result `json:",omitempty"` // beforeScenario/afterScenario invocations
+	Skipped     bool           `json:",omitempty"` // a before-hook failed, so no setup, steps or teardown ran
 	TeardownRan bool
 	Steps       []StepResult
 	DurationMs  float64
@@ -38,6 +52,7 @@ type StepResult struct {
 	Passed     bool
 	StatusCode int
 	Errors     []string
+	StartedAt  time.Time
 	DurationMs float64
 
 	Method      string
@@ -46,6 +61,14 @@ type StepResult struct {
 	ReqBody     string
 	RespHeaders map[string][]string
 	RespBody    string
+
+	// Reported by hooks
+	Checks      []hooks.Assertion  `json:",omitempty"`
+	Attachments []hooks.Attachment `json:",omitempty"`
+	Metrics     map[string]float64 `json:",omitempty"`
+
+	// One per before/after hook invocation, in run order
+	HookResults []hooks.Result `json:",omitempty"`
 }
 
 // ---- Runner ----
@@ -54,8 +77,15 @@ type Runner struct {
 	httpClient *http.Client
 	baseVars   map[string]string
 
-	contractV *contract.Validator
-	covered   map[string]map[string]bool // method -> pathTemplate -> true
+	contracts *contract.Registry
+	covMu     sync.Mutex
+	covered   map[string]map[string]map[string]bool // spec -> method -> pathTemplate -> true
+	drift     *contract.DriftLog
+
+	hooks *hooks.Manager
+
+	redaction *ir.Redaction
+	redactor  *redact.Redactor // built per RunSuite
 
 	parallel int
 	failFast bool
@@ -68,7 +98,7 @@ func New() *Runner {
 		IdleConnTimeout:     90 * time.Second,
 		ForceAttemptHTTP2:   true,
 	}
-	return &Runner{httpClient: &http.Client{Transport: tr}}
+	return &Runner{httpClient: &http.Client{Transport: tr}, hooks: hooks.NewManager()}
 }
 
 func NewWithVars(vars map[string]string) *Runner {
@@ -78,14 +108,32 @@ func NewWithVars(vars map[string]string) *Runner {
 		IdleConnTimeout:     90 * time.Second,
 		ForceAttemptHTTP2:   true,
 	}
-	return &Runner{httpClient: &http.Client{Transport: tr}, baseVars: clone(vars)}
+	return &Runner{httpClient: &http.Client{Transport: tr}, baseVars: clone(vars), hooks: hooks.NewManager()}
 }
 
+// Close stops persistent hook processes started during the run.
+func (r *Runner) Close() error {
+	return r.hooks.Close()
+}
+
+// WithContract registers v as the default spec (matches every request).
 func (r *Runner) WithContract(v *contract.Validator) *Runner {
+	if r.contracts == nil {
+		r.contracts = contract.NewRegistry()
+	}
+	r.contracts.Add("", contract.Match{}, v)
+	return r.WithContracts(r.contracts)
+}
+
+// WithContracts routes contract checks across several specs.
+func (r *Runner) WithContracts(reg *contract.Registry) *Runner {
 	if r.covered == nil {
-		r.covered = map[string]map[string]bool{}
+		r.covered = map[string]map[string]map[string]bool{}
+	}
+	if r.drift == nil {
+		r.drift = contract.NewDriftLog()
 	}
-	r.contractV = v
+	r.contracts = reg
 	return r
 }
 func (r *Runner) WithParallel(n int) *Runner {
@@ -95,8 +143,57 @@ func (r *Runner) WithParallel(n int) *Runner {
 	r.parallel = n
 	return r
 }
-func (r *Runner) WithFailFast(b bool) *Runner         { r.failFast = b; return r }
-func (r *Runner) Covered() map[string]map[string]bool { return r.covered }
+func (r *Runner) WithFailFast(b bool) *Runner { r.failFast = b; return r }
+
+// WithRedaction adds to what is masked in results (see redact.New).
+func (r *Runner) WithRedaction(cfg ir.Redaction) *Runner { r.redaction = &cfg; return r }
+
+// Covered merges coverage across all specs: method -> pathTemplate -> true.
+func (r *Runner) Covered() map[string]map[string]bool {
+	r.covMu.Lock()
+	defer r.covMu.Unlock()
+	out := map[string]map[string]bool{}
+	for _, byMethod := range r.covered {
+		for m, paths := range byMethod {
+			if out[m] == nil {
+				out[m] = map[string]bool{}
+			}
+			for p := range paths {
+				out[m][p] = true
+			}
+		}
+	}
+	return out
+}
+
+// CoveredBySpec is Covered split per registered spec name.
+func (r *Runner) CoveredBySpec() map[string]map[string]map[string]bool {
+	r.covMu.Lock()
+	defer r.covMu.Unlock()
+	out := make(map[string]map[string]map[string]bool, len(r.covered))
+	for name, byMethod := range r.covered {
+		out[name] = map[string]map[string]bool{}
+		for m, paths := range byMethod {
+			out[name][m] = map[string]bool{}
+			for p := range paths {
+				out[name][m][p] = true
+			}
+		}
+	}
+	return out
+}
+
+func (r *Runner) markCovered(spec, method, path string) {
+	r.covMu.Lock()
+	defer r.covMu.Unlock()
+	if r.covered[spec] == nil {
+		r.covered[spec] = map[string]map[string]bool{}
+	}
+	if r.covered[spec][method] == nil {
+		r.covered[spec][method] = map[string]bool{}
+	}
+	r.covered[spec][method][path] = true
+}
 
 // ---- Suite execution ----
 
@@ -118,6 +215,40 @@ func (r *Runner) RunSuite(ctx context.Context, suite *ir.TestSuite) (*SuiteResul
 
 	startSuite := time.Now()
 	res := &SuiteResult{Passed: true, Scenarios: make([]ScenarioResult, len(suite.Scenarios))}
+	red, err := redact.New(redactions(r.redaction, suite.Redact)...)
+	if err != nil {
+		return nil, err
+	}
+	red.AddVars(r.baseVars)
+	r.redactor = red
+	defer redactResult(red, res) // last: after the afterSuite hooks
+
+	if r.drift != nil {
+		defer func() { res.Drift = r.drift.Report() }()
+	}
+
+	// Suite hooks: beforeSuite vars are shared by every scenario.
+	base := clone(r.baseVars)
+	if base == nil {
+		base = map[string]string{}
+	}
+	beforeErrs := r.runScopeHooks(ctx, "beforeSuite", suite.Hooks, base, "", &res.HookResults)
+	defer func() {
+		if errs := r.runScopeHooks(ctx, "afterSuite", suite.Hooks, base, "", &res.HookResults); len(errs) > 0 {
+			res.Passed = false
+			res.Errors = append(res.Errors, errs...)
+		}
+	}()
+	if len(beforeErrs) > 0 {
+		// the steps would run without what the hook provides (e.g. a token)
+		res.Passed = false
+		res.Errors = append(res.Errors, beforeErrs...)
+		for i, sc := range suite.Scenarios {
+			res.Scenarios[i] = ScenarioResult{Name: sc.Name, Skipped: true, Errors: []string{"skipped: beforeSuite hook failed"}}
+		}
+		res.DurationMs = float64(time.Since(startSuite).Milliseconds())
+		return res, nil
+	}
 
 	parallel := r.parallel
 	if r.failFast {
@@ -129,7 +260,7 @@ func (r *Runner) RunSuite(ctx context.Context, suite *ir.TestSuite) (*SuiteResul
 
 	if parallel == 1 {
 		for i, sc := range suite.Scenarios {
-			scRes := r.runScenario(ctx, sc)
+			scRes := r.runScenario(ctx, sc, base, suite.Hooks)
 			if !scRes.Passed {
 				res.Passed = false
 			}
@@ -159,7 +290,7 @@ func (r *Runner) RunSuite(ctx context.Context, suite *ir.TestSuite) (*SuiteResul
 	for w := 0; w < parallel; w++ {
 		go func() {
 			for j := range jobs {
-				results <- result{idx: j.idx, sc: r.runScenario(ctx, j.sc)}
+				results <- result{idx: j.idx, sc: r.runScenario(ctx, j.sc, base, suite.Hooks)}
 			}
 		}()
 	}
@@ -182,8 +313,10 @@ func (r *Runner) RunSuite(ctx context.Context, suite *ir.TestSuite) (*SuiteResul
 	return res, nil
 }
 
-func (r *Runner) runScenario(ctx context.Context, sc ir.Scenario) ScenarioResult {
-	vars := clone(r.baseVars)
+// runScenario runs sc with a copy of base; suiteHooks contribute their
+// beforeScenario/afterScenario hooks around the scenario's own.
+func (r *Runner) runScenario(ctx context.Context, sc ir.Scenario, base map[string]string, suiteHooks []ir.Hook) ScenarioResult {
+	vars := clone(base)
 	if vars == nil {
 		vars = map[string]string{}
 	}
@@ -193,36 +326,47 @@ func (r *Runner) runScenario(ctx context.Context, sc ir.Scenario) ScenarioResult
 	startSc := time.Now()
 	scRes := ScenarioResult{Name: sc.Name, Passed: true}
 
+	// beforeScenario: suite-wide hooks first, then the scenario's own
+	scopeErrs := r.runScopeHooks(ctx, "beforeScenario", suiteHooks, vars, sc.Name, &scRes.HookResults)
+	scopeErrs = append(scopeErrs, r.runScopeHooks(ctx, "beforeScenario", sc.Hooks, vars, sc.Name, &scRes.HookResults)...)
+	if len(scopeErrs) > 0 {
+		scRes.Passed = false
+		scRes.Skipped = true
+		scRes.Errors = append(scRes.Errors, scopeErrs...)
+		scRes.Errors = append(scRes.Errors, "skipped: beforeScenario hook failed")
+		// afterScenario hooks still run to release what was acquired
+		sc.Setup, sc.Steps, sc.Teardown = nil, nil, nil
+	}
+
 	// Setup (best-effort)
 	if err := r.runActions(ctx, sc.Setup, vars); err != nil {
 		scRes.Passed = false
 	}
 
 	// Steps
-	for _, st := range sc.Steps {
-		stepRes := StepResult{Passed: true}
+	for i, st := range sc.Steps {
+		stepRes := StepResult{Name: st.Name, Passed: true}
 		req := expandRequest(st.Request, vars)
 
+		stepHooks := withScripts(st)
+		trace := hooks.Trace{Label: fmt.Sprintf("%s / step %d", sc.Name, i+1), Mask: r.redactor.String}
+
 		// BEFORE hooks
-		for _, hk := range st.Hooks {
+		for _, hk := range stepHooks {
 			if strings.ToLower(hk.When) != "before" {
 				continue
 			}
-			out, err := hooks.RunProcessHook(ctx, "before", hk, hooks.Input{
+			out, hr, err := r.hooks.Exec(ctx, "before", expandHook(hk, vars), hooks.Input{
 				Vars:    clone(vars),
 				Request: &req,
-			})
+			}, trace)
+			stepRes.HookResults = append(stepRes.HookResults, hr)
 			if err != nil {
 				stepRes.Passed = false
 				stepRes.Errors = append(stepRes.Errors, fmt.Sprintf("hook(before) error: %v", err))
 				continue
 			}
-			// apply returned vars
-			for k, v := range out.Vars {
-				if v != "" {
-					vars[k] = v
-				}
-			}
+			r.mergeVars(vars, hk, out)
 			// apply request patch (if any)
 			if out.Request != nil {
 				if out.Request.URL != "" {
@@ -246,6 +390,7 @@ func (r *Runner) runScenario(ctx context.Context, sc ir.Scenario) ScenarioResult
 				stepRes.Passed = false
 				stepRes.Errors = append(stepRes.Errors, out.Errors...)
 			}
+			recordOutput(&stepRes, out)
 		}
 
 		// Capture request details for report (after hooks have possibly mutated it)
@@ -266,13 +411,15 @@ func (r *Runner) runScenario(ctx context.Context, sc ir.Scenario) ScenarioResult
 		}
 
 		startStep := time.Now()
+		stepRes.StartedAt = startStep
 		status, body, respHdrs, err := r.doRequest(ctx, req)
 		stepRes.DurationMs = float64(time.Since(startStep).Milliseconds())
 
 		// Capture response
 		stepRes.StatusCode = status
 		stepRes.RespHeaders = respHdrs
-		stepRes.RespBody = limitBody(body, 64<<10) // 64KB cap in report
+		// JSON paths are masked before the 64KB report cap: a cut body no longer parses
+		stepRes.RespBody = limitBody([]byte(r.redactor.Body(string(body))), 64<<10)
 
 		if err != nil {
 			stepRes.Passed = false
@@ -280,12 +427,12 @@ func (r *Runner) runScenario(ctx context.Context, sc ir.Scenario) ScenarioResult
 		}
 
 		// AFTER hooks
-		for _, hk := range st.Hooks {
+		for _, hk := range stepHooks {
 			if strings.ToLower(hk.When) != "after" {
 				continue
 			}
 			raw := json.RawMessage(body) // may be non-JSON; still pass through
-			out, err := hooks.RunProcessHook(ctx, "after", hk, hooks.Input{
+			out, hr, err := r.hooks.Exec(ctx, "after", expandHook(hk, vars), hooks.Input{
 				Vars:    clone(vars),
 				Request: &req,
 				Response: &hooks.Resp{
@@ -293,21 +440,19 @@ func (r *Runner) runScenario(ctx context.Context, sc ir.Scenario) ScenarioResult
 					Headers: respHdrs,
 					Body:    raw,
 				},
-			})
+			}, trace)
+			stepRes.HookResults = append(stepRes.HookResults, hr)
 			if err != nil {
 				stepRes.Passed = false
 				stepRes.Errors = append(stepRes.Errors, fmt.Sprintf("hook(after) error: %v", err))
 				continue
 			}
-			for k, v := range out.Vars {
-				if v != "" {
-					vars[k] = v
-				}
-			}
+			r.mergeVars(vars, hk, out)
 			if len(out.Errors) > 0 {
 				stepRes.Passed = false
 				stepRes.Errors = append(stepRes.Errors, out.Errors...)
 			}
+			recordOutput(&stepRes, out)
 		}
 
 		// Parse JSON body (best-effort) for expectations
@@ -335,7 +480,15 @@ func (r *Runner) runScenario(ctx context.Context, sc ir.Scenario) ScenarioResult
 	}
 
 	_ = r.runActions(ctx, sc.Teardown, vars)
-	scRes.TeardownRan = true
+	scRes.TeardownRan = !scRes.Skipped
+
+	// afterScenario: in reverse nesting order
+	scopeErrs = r.runScopeHooks(ctx, "afterScenario", sc.Hooks, vars, sc.Name, &scRes.HookResults)
+	scopeErrs = append(scopeErrs, r.runScopeHooks(ctx, "afterScenario", suiteHooks, vars, sc.Name, &scRes.HookResults)...)
+	if len(scopeErrs) > 0 {
+		scRes.Passed = false
+		scRes.Errors = append(scRes.Errors, scopeErrs...)
+	}
 	scRes.DurationMs = float64(time.Since(startSc).Milliseconds())
 
 	return scRes
@@ -452,17 +605,35 @@ func (r *Runner) evalExpectation(
 		return true, ""
 
 	case ir.ExpectContract:
-		if r.contractV == nil {
+		if r.contracts == nil || r.contracts.Len() == 0 {
 			return false, "contract: requested but no OpenAPI spec configured"
 		}
-		path, mth, err := r.contractV.ValidateResponse(context.Background(), method, url, status, respHeaders, rawBody)
+		route, specURL, ok := r.contracts.Resolve(url, func(s string) string { return interpolate(s, vars) })
+		if !ok {
+			return false, fmt.Sprintf("contract: no OpenAPI spec matches %s", url)
+		}
+		path, mth, err := route.Validator.ValidateResponse(context.Background(), method, specURL, status, respHeaders, rawBody)
+		if dp, dm, findings, derr := route.Validator.Drift(method, specURL, status, respHeaders, rawBody); derr == nil {
+			r.drift.Add(route.Name, dm, dp, findings)
+		}
 		if err != nil {
+			if route.Name != "" {
+				return false, fmt.Sprintf("contract[%s]: %v", route.Name, err)
+			}
 			return false, fmt.Sprintf("contract: %v", err)
 		}
-		if r.covered[mth] == nil {
-			r.covered[mth] = map[string]bool{}
+		r.markCovered(route.Name, mth, path)
+		return true, ""
+
+	case ir.ExpectExpr:
+		src, _ := exp.Value.(string)
+		if src == "" {
+			src = exp.Target
+		}
+		env := assertion.Response{Status: status, Headers: respHeaders, Body: rawBody, Vars: vars}.Env()
+		if msg := assertion.Check(src, env); msg != "" {
+			return false, msg
 		}
-		r.covered[mth][path] = true
 		return true, ""
 
 	default:
@@ -470,6 +641,212 @@ func (r *Runner) evalExpectation(
 	}
 }
 
+// ---- Redaction ----
+
+func redactions(cfgs ...*ir.Redaction) []ir.Redaction {
+	var out []ir.Redaction
+	for _, c := range cfgs {
+		if c != nil {
+			out = append(out, *c)
+		}
+	}
+	return out
+}
+
+// redactResult masks secrets in everything the reports and stderr show.
+func redactResult(red *redact.Redactor, res *SuiteResult) {
+	red.Strings(res.Errors)
+	redactHookResults(red, res.HookResults)
+	for i := range res.Scenarios {
+		sc := &res.Scenarios[i]
+		red.Strings(sc.Errors)
+		redactHookResults(red, sc.HookResults)
+		for j := range sc.Steps {
+			st := &sc.Steps[j]
+			red.Strings(st.Errors)
+			st.URL = red.String(st.URL)
+			st.ReqHeaders = red.Headers(st.ReqHeaders)
+			st.ReqBody = red.Body(st.ReqBody)
+			st.RespHeaders = red.HeaderValues(st.RespHeaders)
+			st.RespBody = red.Body(st.RespBody)
+			for k := range st.Checks {
+				c := &st.Checks[k]
+				c.Message = red.String(c.Message)
+				c.Expected = red.Value(c.Expected)
+				c.Actual = red.Value(c.Actual)
+			}
+			for k := range st.Attachments {
+				a := &st.Attachments[k]
+				a.Text = red.String(a.Text)
+				a.JSON = red.RawJSON(a.JSON)
+			}
+			redactHookResults(red, st.HookResults)
+		}
+	}
+}
+
+func redactHookResults(red *redact.Redactor, hrs []hooks.Result) {
+	for i := range hrs {
+		hr := &hrs[i]
+		hr.Name = red.String(hr.Name)
+		hr.Stderr = red.String(hr.Stderr)
+		hr.Error = red.String(hr.Error)
+		if hr.Output == nil {
+			continue
+		}
+		o := *hr.Output
+		if o.Vars != nil {
+			vars := make(map[string]string, len(o.Vars))
+			for k, v := range o.Vars {
+				if red.SecretVar(k) {
+					v = redact.Mask
+				}
+				vars[k] = red.String(v)
+			}
+			o.Vars = vars
+		}
+		if o.Request != nil {
+			p := *o.Request
+			p.URL = red.String(p.URL)
+			p.Headers = red.Headers(p.Headers)
+			p.Body = red.Value(p.Body)
+			o.Request = &p
+		}
+		o.Errors = slices.Clone(o.Errors)
+		red.Strings(o.Errors)
+		o.Redact = slices.Repeat([]string{redact.Mask}, len(o.Redact))
+		hr.Output = &o
+	}
+}
+
+// ---- Hooks ----
+
+// runScopeHooks runs the hooks in hs registered for when (a scenario or
+// suite phase), merging returned vars into vars and appending each
+// invocation to results. It returns hook failures.
+func (r *Runner) runScopeHooks(ctx context.Context, when string, hs []ir.Hook, vars map[string]string, scenario string, results *[]hooks.Result) []string {
+	label := scenario
+	if label == "" {
+		label = "suite"
+	}
+	trace := hooks.Trace{Label: label, Mask: r.redactor.String}
+	var errs []string
+	for _, hk := range hs {
+		if !strings.EqualFold(hk.When, when) {
+			continue
+		}
+		out, hr, err := r.hooks.Exec(ctx, when, expandHook(hk, vars), hooks.Input{Vars: clone(vars), Scenario: scenario}, trace)
+		*results = append(*results, hr)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("hook(%s) error: %v", when, err))
+			continue
+		}
+		r.mergeVars(vars, hk, out)
+		errs = append(errs, out.Errors...)
+	}
+	return errs
+}
+
+// mergeVars applies the vars a hook returned and registers its secrets:
+// values of secret vars (by pattern or the hook's redact list) and the
+// values it asked to redact.
+func (r *Runner) mergeVars(vars map[string]string, hk ir.Hook, out *hooks.Output) {
+	for k, v := range out.Vars {
+		if v == "" {
+			continue
+		}
+		vars[k] = v
+		if r.redactor.SecretVar(k) || slices.Contains(hk.Redact, k) {
+			r.redactor.AddSecret(v)
+		}
+	}
+	for _, v := range out.Redact {
+		r.redactor.AddSecret(v)
+	}
+}
+
+// recordOutput adds a hook's assertions, attachments and metrics to st.
+func recordOutput(st *StepResult, out *hooks.Output) {
+	for _, a := range out.Assertions {
+		if !a.Passed {
+			st.Passed = false
+		}
+		st.Checks = append(st.Checks, a)
+	}
+	for _, a := range out.Attachments {
+		st.Attachments = append(st.Attachments, loadAttachment(a))
+	}
+	for k, v := range out.Metrics {
+		if st.Metrics == nil {
+			st.Metrics = map[string]float64{}
+		}
+		st.Metrics[k] = v
+	}
+}
+
+// maxAttachment caps file attachments inlined into results.
+const maxAttachment = 256 << 10
+
+// loadAttachment inlines a file attachment's text so reports are
+// self-contained; binary or oversized files keep only their path.
+func loadAttachment(a hooks.Attachment) hooks.Attachment {
+	switch {
+	case a.Path != "" && a.Text == "" && a.JSON == nil:
+		if a.ContentType == "" {
+			a.ContentType = mime.TypeByExtension(filepath.Ext(a.Path))
+		}
+		b, err := os.ReadFile(a.Path)
+		switch {
+		case err != nil:
+			a.Text = fmt.Sprintf("(unreadable: %v)", err)
+		case len(b) > maxAttachment:
+			a.Text = fmt.Sprintf("(%d bytes; not inlined)", len(b))
+		case !utf8.Valid(b):
+			a.Text = fmt.Sprintf("(binary, %d bytes; not inlined)", len(b))
+		case json.Valid(b) && (a.ContentType == "" || strings.Contains(a.ContentType, "json")):
+			a.JSON = json.RawMessage(b)
+		default:
+			a.Text = string(b)
+		}
+	case a.JSON != nil && a.ContentType == "":
+		a.ContentType = "application/json"
+	case a.ContentType == "":
+		a.ContentType = "text/plain"
+	}
+	return a
+}
+
+// withScripts appends the step's script shorthand to its hooks.
+func withScripts(st ir.Step) []ir.Hook {
+	if st.Script == nil {
+		return st.Hooks
+	}
+	hs := append([]ir.Hook(nil), st.Hooks...)
+	if st.Script.Before != "" {
+		hs = append(hs, ir.Hook{Type: "script", When: "before", Script: st.Script.Before})
+	}
+	if st.Script.After != "" {
+		hs = append(hs, ir.Hook{Type: "script", When: "after", Script: st.Script.After})
+	}
+	return hs
+}
+
+// expandHook resolves ${VARS} in an http hook's URL and headers.
+func expandHook(h ir.Hook, vars map[string]string) ir.Hook {
+	if h.Type != "http" {
+		return h
+	}
+	h.URL = interpolate(h.URL, vars)
+	if len(h.Headers) > 0 {
+		hs := make(map[string]string, len(h.Headers))
+		for k, v := range h.Headers {
+			hs[k] = interpolate(v, vars)
+		}
+		h.Headers = hs
+	}
+	return h
+}
+
 // ---- Interpolation (with defaults + unresolved guard) ----
 
 var varPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
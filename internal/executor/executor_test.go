@@ -5,6 +5,10 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -198,3 +202,329 @@ func TestExecutor_TeardownRunsOnFailure_AndIsolation(t *testing.T) {
 		t.Fatalf("want 1 passed and 1 failed scenario, got passed=%d failed=%d", passed, failed)
 	}
 }
+
+func TestExecutor_ExprExpectations(t *testing.T) {
+	srv, _ := newTestServer()
+	defer srv.Close()
+
+	step := func(expr string) ir.Step {
+		return ir.Step{
+			Request: ir.Request{Method: http.MethodPost, URL: srv.URL + "/users", Body: map[string]any{"name": "Test User"}},
+			Expect:  []ir.Expectation{{Type: ir.ExpectExpr, Value: expr}},
+		}
+	}
+	suite := &ir.TestSuite{
+		Name: "Expr",
+		Scenarios: []ir.Scenario{{
+			Name: "expressions",
+			Steps: []ir.Step{
+				step(`status == 201 && body.name == "Test User" && header("content-type") startsWith "application/json"`),
+				step(`status == 201 && body.name == vars.WANT`),
+			},
+		}},
+	}
+
+	res, err := executor.NewWithVars(map[string]string{"WANT": "Other"}).RunSuite(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("RunSuite error: %v", err)
+	}
+	steps := res.Scenarios[0].Steps
+	if !steps[0].Passed {
+		t.Errorf("passing expression failed: %v", steps[0].Errors)
+	}
+	want := `expr status == 201 && body.name == vars.WANT: false (body.name = "Test User"; vars.WANT = "Other")`
+	if steps[1].Passed || len(steps[1].Errors) != 1 || steps[1].Errors[0] != want {
+		t.Errorf("errors = %q\nwant   [%q]", steps[1].Errors, want)
+	}
+}
+
+func TestExecutor_StepScripts(t *testing.T) {
+	srv, _ := newTestServer()
+	defer srv.Close()
+
+	suite := &ir.TestSuite{
+		Name: "Scripts",
+		Scenarios: []ir.Scenario{{
+			Name: "script sets body and reads response",
+			Steps: []ir.Step{
+				{
+					Request: ir.Request{Method: http.MethodPost, URL: srv.URL + "/users", Body: map[string]any{}},
+					Script: &ir.StepScript{
+						Before: `request["body"]["email"] = "a@" + vars["DOMAIN"]`,
+						After: `
+vars["USER_ID"] = response["body"]["id"]
+if response["body"]["email"] != "a@example.com":
+    error("email not echoed")
+`,
+					},
+				},
+				{
+					Request: ir.Request{Method: http.MethodPost, URL: srv.URL + "/users", Body: map[string]any{"name": "${USER_ID}"}},
+					Script:  &ir.StepScript{After: `error("created " + response["body"]["name"])`},
+				},
+			},
+		}},
+	}
+
+	res, err := executor.NewWithVars(map[string]string{"DOMAIN": "example.com"}).RunSuite(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("RunSuite error: %v", err)
+	}
+	steps := res.Scenarios[0].Steps
+	if !steps[0].Passed {
+		t.Fatalf("step 1 errors: %v", steps[0].Errors)
+	}
+	if steps[1].Passed || len(steps[1].Errors) != 1 || steps[1].Errors[0] != "created u-123" {
+		t.Fatalf("step 2 errors = %v, want [created u-123]", steps[1].Errors)
+	}
+}
+
+func TestExecutor_ScenarioAndSuiteHooks(t *testing.T) {
+	srv, _ := newTestServer()
+	defer srv.Close()
+
+	var mu sync.Mutex
+	var phases []string
+	hookSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		phases = append(phases, r.Header.Get("X-Seaqa-Hook-When"))
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"vars":{"TOKEN":"tok"}}`))
+	}))
+	defer hookSrv.Close()
+
+	step := ir.Step{
+		Request: ir.Request{Method: http.MethodPost, URL: srv.URL + "/users", Body: map[string]any{"email": "${TOKEN}-${SEED}"}},
+		Expect:  []ir.Expectation{{Type: ir.ExpectJSONPath, Target: "$.email", Value: "tok-${SCENARIO}"}},
+	}
+	seed := ir.Hook{Type: "script", When: "beforeScenario", Script: `vars["SEED"] = vars["SCENARIO"]`}
+	suite := &ir.TestSuite{
+		Name: "Scoped hooks",
+		Hooks: []ir.Hook{
+			{Type: "http", When: "beforeSuite", URL: hookSrv.URL},
+			{Type: "http", When: "afterSuite", URL: hookSrv.URL},
+			{Type: "script", When: "afterScenario", Script: `error("cleanup failed") if vars["SEED"] == "b" else None`},
+		},
+		Scenarios: []ir.Scenario{
+			{Name: "a", Steps: []ir.Step{step}, Hooks: []ir.Hook{{Type: "script", When: "beforeScenario", Script: `vars["SCENARIO"] = "a"`}, seed}},
+			{Name: "b", Steps: []ir.Step{step}, Hooks: []ir.Hook{{Type: "script", When: "beforeScenario", Script: `vars["SCENARIO"] = "b"`}, seed}},
+		},
+	}
+
+	r := executor.New().WithParallel(2)
+	defer r.Close()
+	res, err := r.RunSuite(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("RunSuite error: %v", err)
+	}
+	if got := strings.Join(phases, ","); got != "beforeSuite,afterSuite" {
+		t.Errorf("suite hook calls = %s", got)
+	}
+	for _, sc := range res.Scenarios {
+		if !sc.Steps[0].Passed {
+			t.Errorf("%s: step errors %v", sc.Name, sc.Steps[0].Errors)
+		}
+	}
+	if !res.Scenarios[0].Passed || res.Scenarios[1].Passed {
+		t.Errorf("passed = %v, %v; want true, false", res.Scenarios[0].Passed, res.Scenarios[1].Passed)
+	}
+	if errs := res.Scenarios[1].Errors; len(errs) != 1 || errs[0] != "cleanup failed" {
+		t.Errorf("scenario b errors = %v", errs)
+	}
+	if hrs := res.HookResults; len(hrs) != 2 || hrs[0].When != "beforeSuite" || hrs[1].Status != 200 {
+		t.Errorf("suite hook results = %+v", hrs)
+	}
+	if hrs := res.Scenarios[1].HookResults; len(hrs) != 3 || hrs[2].When != "afterScenario" || hrs[2].Output.Errors[0] != "cleanup failed" {
+		t.Errorf("scenario hook results = %+v", hrs)
+	}
+}
+
+func TestExecutor_FailedBeforeHooksSkipSteps(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	step := ir.Step{Request: ir.Request{Method: http.MethodGet, URL: srv.URL}}
+	action := ir.Action{Request: &ir.Request{Method: http.MethodGet, URL: srv.URL}}
+	after := ir.Hook{Type: "script", When: "afterScenario", Script: `vars["X"] = "1"`}
+	fail := `error("no token")`
+
+	// a failing beforeScenario skips that scenario only; afterScenario still runs
+	suite := &ir.TestSuite{Scenarios: []ir.Scenario{
+		{Name: "a", Setup: []ir.Action{action}, Steps: []ir.Step{step}, Teardown: []ir.Action{action},
+			Hooks: []ir.Hook{{Type: "script", When: "beforeScenario", Script: fail}, after}},
+		{Name: "b", Steps: []ir.Step{step}},
+	}}
+	res, err := executor.New().RunSuite(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("RunSuite error: %v", err)
+	}
+	a := res.Scenarios[0]
+	if a.Passed || !a.Skipped || len(a.Steps) != 0 || a.TeardownRan {
+		t.Errorf("scenario a = %+v, want skipped", a)
+	}
+	if len(a.Errors) != 2 || a.Errors[1] != "skipped: beforeScenario hook failed" {
+		t.Errorf("scenario a errors = %v", a.Errors)
+	}
+	if len(a.HookResults) != 2 || a.HookResults[1].When != "afterScenario" {
+		t.Errorf("scenario a hook results = %+v", a.HookResults)
+	}
+	if b := res.Scenarios[1]; !b.Passed || b.Skipped {
+		t.Errorf("scenario b = %+v", b)
+	}
+	if calls != 1 {
+		t.Errorf("requests = %d, want 1 (scenario b only)", calls)
+	}
+
+	// a failing beforeSuite skips every scenario; afterSuite still runs
+	calls = 0
+	suite.Hooks = []ir.Hook{
+		{Type: "script", When: "beforeSuite", Script: fail},
+		{Type: "script", When: "afterSuite", Script: `vars["X"] = "1"`},
+	}
+	res, err = executor.New().RunSuite(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("RunSuite error: %v", err)
+	}
+	if res.Passed || len(res.Errors) != 1 || len(res.HookResults) != 2 {
+		t.Errorf("suite = %+v", res)
+	}
+	for _, sc := range res.Scenarios {
+		if !sc.Skipped || sc.Passed || len(sc.Steps) != 0 || len(sc.Errors) != 1 {
+			t.Errorf("scenario %s = %+v, want skipped", sc.Name, sc)
+		}
+	}
+	if calls != 0 {
+		t.Errorf("requests = %d, want 0", calls)
+	}
+}
+
+func TestExecutor_RedactsTruncatedBodies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"password":"hunter22","zpad":"` + strings.Repeat("x", 100<<10) + `"}`))
+	}))
+	defer srv.Close()
+
+	suite := &ir.TestSuite{Scenarios: []ir.Scenario{{
+		Name:  "big",
+		Steps: []ir.Step{{Request: ir.Request{Method: http.MethodGet, URL: srv.URL}}},
+	}}}
+	res, err := executor.New().WithRedaction(ir.Redaction{JSONPaths: []string{"$.password"}}).RunSuite(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("RunSuite error: %v", err)
+	}
+	body := res.Scenarios[0].Steps[0].RespBody
+	if strings.Contains(body, "hunter22") || !strings.Contains(body, `"password":"***"`) || !strings.HasSuffix(body, "...[truncated]...") {
+		t.Fatalf("body = %.80q...", body)
+	}
+}
+
+func TestExecutor_HookChecksAndAttachments(t *testing.T) {
+	srv, _ := newTestServer()
+	defer srv.Close()
+
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "server.log")
+	if err := os.WriteFile(logPath, []byte("GET /users 201\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	hookSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"assertions":  []map[string]any{{"name": "audit row written", "passed": false, "expected": 1, "actual": 0}},
+			"attachments": []map[string]any{{"name": "server log", "path": logPath}},
+			"metrics":     map[string]float64{"db_ms": 12.5},
+		})
+	}))
+	defer hookSrv.Close()
+
+	suite := &ir.TestSuite{Scenarios: []ir.Scenario{{
+		Name: "checks",
+		Steps: []ir.Step{{
+			Request: ir.Request{Method: http.MethodPost, URL: srv.URL + "/users", Body: map[string]any{}},
+			Hooks:   []ir.Hook{{Type: "http", When: "after", URL: hookSrv.URL}},
+			Script:  &ir.StepScript{After: `check("created", response["status"] == 201)`},
+		}},
+	}}}
+
+	res, err := executor.New().RunSuite(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("RunSuite error: %v", err)
+	}
+	st := res.Scenarios[0].Steps[0]
+	if st.Passed || len(st.Errors) != 0 {
+		t.Errorf("passed = %v, errors = %v; want a failure from checks only", st.Passed, st.Errors)
+	}
+	if len(st.Checks) != 2 || st.Checks[0].Name != "audit row written" || !st.Checks[1].Passed {
+		t.Errorf("checks = %+v", st.Checks)
+	}
+	if len(st.Attachments) != 1 || st.Attachments[0].Text != "GET /users 201\n" {
+		t.Errorf("attachments = %+v", st.Attachments)
+	}
+	if st.Metrics["db_ms"] != 12.5 {
+		t.Errorf("metrics = %v", st.Metrics)
+	}
+}
+
+func TestExecutor_RedactsResults(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "sid=abc123")
+		_, _ = w.Write([]byte(`{"user":"ann","password":"p@ss","echo":"` + r.Header.Get("X-Signature") + `"}`))
+	}))
+	defer srv.Close()
+
+	suite := &ir.TestSuite{
+		Redact: &ir.Redaction{JSONPaths: []string{"$.password"}},
+		Scenarios: []ir.Scenario{{
+			Name: "secrets",
+			Steps: []ir.Step{{
+				Request: ir.Request{
+					Method:  http.MethodGet,
+					URL:     srv.URL + "/me?key=${CLIENT_KEY}",
+					Headers: map[string]string{"Authorization": "Bearer ${API_TOKEN}"},
+				},
+				Hooks: []ir.Hook{{
+					Type: "script", When: "before", Redact: []string{"SIG"},
+					Script: `vars["SIG"] = "sig-" + vars["API_TOKEN"]
+print("signing with " + vars["API_TOKEN"])
+request["headers"]["X-Signature"] = vars["SIG"]`,
+				}},
+				Expect: []ir.Expectation{{Type: ir.ExpectJSONPath, Target: "$.user", Value: "${CLIENT_KEY}"}},
+			}},
+		}},
+	}
+
+	r := executor.NewWithVars(map[string]string{"API_TOKEN": "t0k3n", "CLIENT_KEY": "ck-42"}).
+		WithRedaction(ir.Redaction{Vars: []string{"CLIENT_*"}})
+	res, err := r.RunSuite(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("RunSuite error: %v", err)
+	}
+	b, _ := json.Marshal(res)
+	for _, secret := range []string{"t0k3n", "ck-42", "sig-", "abc123", "p@ss"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("result contains %q:\n%s", secret, b)
+		}
+	}
+	st := res.Scenarios[0].Steps[0]
+	if st.URL != srv.URL+"/me?key=***" || st.ReqHeaders["Authorization"] != "***" {
+		t.Errorf("url %q, headers %v", st.URL, st.ReqHeaders)
+	}
+	if len(st.Errors) != 1 || st.Errors[0] != "jsonPath $.user: got ann, want ***" {
+		t.Errorf("errors = %v", st.Errors)
+	}
+	if len(st.HookResults) != 1 || st.HookResults[0].Stderr != "signing with ***\n" || st.HookResults[0].Output.Vars["SIG"] != "***" {
+		t.Errorf("hook results = %+v", st.HookResults)
+	}
+
+	suite.Redact = &ir.Redaction{JSONPaths: []string{"$.["}}
+	if _, err := r.RunSuite(context.Background(), suite); err == nil {
+		t.Error("expected error for a bad redaction path")
+	}
+}
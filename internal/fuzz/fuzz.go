@@ -0,0 +1,628 @@
+package fuzz
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"sea-qa/internal/generate"
+	"sea-qa/internal/ir"
+	"sea-qa/internal/sample"
+)
+
+// Case kinds. Every kind except KindBoundary is an invalid request.
+const (
+	KindMissingRequired = "missing-required"
+	KindWrongType       = "wrong-type"
+	KindOutOfRange      = "out-of-range"
+	KindTooLong         = "too-long"
+	KindTooShort        = "too-short"
+	KindBadEnum         = "bad-enum"
+	KindBadFormat       = "bad-format"
+	KindUnknownProperty = "unknown-property"
+	KindMalformedJSON   = "malformed-json"
+	KindBoundary        = "boundary"
+)
+
+type Options struct {
+	Seed     int64
+	MaxCases int    // per operation; 0 = no limit
+	BaseVar  string // variable holding the base URL; defaults to BASE_URL
+}
+
+// Case is one generated request. Target names what was mutated, e.g.
+// "body.address.zip", "query.limit", "path.petId" or "body".
+type Case struct {
+	ID      string     `json:"id"`
+	Kind    string     `json:"kind"`
+	Target  string     `json:"target"`
+	Detail  string     `json:"detail,omitempty"`
+	Valid   bool       `json:"valid"`
+	Request ir.Request `json:"request"`
+}
+
+type OperationPlan struct {
+	Method string
+	Path   string
+	Op     *openapi3.Operation
+	Cases  []Case
+}
+
+// Plan holds the cases for every operation; the same doc and seed always
+// produce the same plan.
+type Plan struct {
+	Seed       int64
+	Operations []OperationPlan
+}
+
+const maxDepth = 6
+
+// Generate builds cases for every operation in doc.
+func Generate(doc *openapi3.T, opt Options) *Plan {
+	baseVar := opt.BaseVar
+	if baseVar == "" {
+		baseVar = "BASE_URL"
+	}
+	plan := &Plan{Seed: opt.Seed}
+	for _, o := range generate.Operations(doc) {
+		g := &gen{rng: opRand(opt.Seed, o.Method+" "+o.Path), seen: map[string]int{}}
+		g.base = generate.BuildRequest(o, baseVar)
+		g.params(o)
+		g.body(o.Op)
+
+		cases := g.cases
+		if opt.MaxCases > 0 && len(cases) > opt.MaxCases {
+			keep := g.rng.Perm(len(cases))[:opt.MaxCases]
+			sort.Ints(keep)
+			picked := make([]Case, 0, len(keep))
+			for _, i := range keep {
+				picked = append(picked, cases[i])
+			}
+			cases = picked
+		}
+		plan.Operations = append(plan.Operations, OperationPlan{
+			Method: o.Method, Path: o.Path, Op: o.Op, Cases: cases,
+		})
+	}
+	return plan
+}
+
+// Suite turns the plan into a runnable suite: one scenario per operation,
+// one step per case. Steps carry no expectations; see Evaluate.
+func (p *Plan) Suite(name string) *ir.TestSuite {
+	suite := &ir.TestSuite{Name: name}
+	for _, op := range p.Operations {
+		sc := ir.Scenario{Name: "fuzz " + op.Method + " " + op.Path, Tags: []string{"fuzz"}}
+		for _, c := range op.Cases {
+			sc.Steps = append(sc.Steps, ir.Step{Name: c.ID, Request: c.Request})
+		}
+		suite.Scenarios = append(suite.Scenarios, sc)
+	}
+	return suite
+}
+
+// opRand derives a per-operation stream so adding an operation does not
+// reshuffle the cases of the others.
+func opRand(seed int64, key string) *rand.Rand {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return rand.New(rand.NewPCG(uint64(seed), h.Sum64()))
+}
+
+type gen struct {
+	rng   *rand.Rand
+	base  ir.Request
+	cases []Case
+	seen  map[string]int
+}
+
+func (g *gen) add(kind, target, detail string, req ir.Request) {
+	id := target + ":" + kind
+	if detail != "" {
+		id += ":" + detail
+	}
+	if n := g.seen[id]; n > 0 {
+		g.seen[id] = n + 1
+		id += "#" + strconv.Itoa(n+1)
+	} else {
+		g.seen[id] = 1
+	}
+	g.cases = append(g.cases, Case{
+		ID: id, Kind: kind, Target: target, Detail: detail,
+		Valid: kind == KindBoundary, Request: req,
+	})
+}
+
+// ---- Parameters ----
+
+func (g *gen) params(o generate.Operation) {
+	for _, p := range generate.Parameters(o) {
+		target := p.In + "." + p.Name
+		if p.Required && p.In != openapi3.ParameterInPath {
+			req := g.baseReq()
+			switch p.In {
+			case openapi3.ParameterInQuery:
+				req.URL = setQuery(req.URL, p.Name, nil)
+			case openapi3.ParameterInHeader:
+				delete(req.Headers, p.Name)
+			default:
+				continue
+			}
+			g.add(KindMissingRequired, target, "", req)
+		}
+		if p.Schema == nil || p.Schema.Value == nil {
+			continue
+		}
+		// Non-required query params are absent from the base request; the
+		// mutations below still add them so their validation is exercised.
+		for _, m := range mutations(p.Schema.Value, g.rng) {
+			if m.kind == KindWrongType && p.Schema.Value.Type.Includes(openapi3.TypeString) {
+				continue // every parameter value is a string on the wire
+			}
+			s, ok := paramString(m.value)
+			if !ok {
+				continue
+			}
+			req := g.baseReq()
+			switch p.In {
+			case openapi3.ParameterInPath:
+				req.URL = setPath(req.URL, p.Name, s)
+			case openapi3.ParameterInQuery:
+				req.URL = setQuery(req.URL, p.Name, &s)
+			case openapi3.ParameterInHeader:
+				req.Headers[p.Name] = s
+			default:
+				continue
+			}
+			g.add(m.kind, target, m.detail, req)
+		}
+	}
+}
+
+func (g *gen) baseReq() ir.Request {
+	req := g.base
+	req.Headers = map[string]string{}
+	for k, v := range g.base.Headers {
+		req.Headers[k] = v
+	}
+	req.Body = rootBody(deepCopy(g.base.Body))
+	return req
+}
+
+func setPath(u, name, val string) string {
+	re := regexp.MustCompile(`\$\{` + regexp.QuoteMeta(name) + `(\|[^}]*)?\}`)
+	return re.ReplaceAllLiteralString(u, url.PathEscape(val))
+}
+
+// setQuery sets (or, with a nil value, removes) a query parameter. The URL
+// still contains ${VAR} placeholders, so it is not parsed as a whole.
+func setQuery(u, name string, val *string) string {
+	base, raw, _ := strings.Cut(u, "?")
+	q, err := url.ParseQuery(raw)
+	if err != nil {
+		q = url.Values{}
+	}
+	if val == nil {
+		q.Del(name)
+	} else {
+		q.Set(name, *val)
+	}
+	if len(q) == 0 {
+		return base
+	}
+	return base + "?" + q.Encode()
+}
+
+func paramString(v any) (string, bool) {
+	switch x := v.(type) {
+	case string:
+		return x, true
+	case bool:
+		return strconv.FormatBool(x), true
+	case int64:
+		return strconv.FormatInt(x, 10), true
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// ---- Request body ----
+
+func (g *gen) body(op *openapi3.Operation) {
+	if op.RequestBody == nil || op.RequestBody.Value == nil {
+		return
+	}
+	rb := op.RequestBody.Value
+	ct, mt := generate.PickMediaType(rb.Content)
+	if mt == nil || !strings.Contains(ct, "json") {
+		return
+	}
+	g.base.Body = sample.FromMediaType(mt, sample.Request)
+	if g.base.Headers == nil {
+		g.base.Headers = map[string]string{}
+	}
+	g.base.Headers["Content-Type"] = ct
+
+	if rb.Required {
+		req := g.baseReq()
+		req.Body = nil
+		g.add(KindMissingRequired, "body", "", req)
+	}
+
+	raw, err := json.Marshal(g.base.Body)
+	if err == nil {
+		req := g.baseReq()
+		if len(raw) > 1 && (raw[0] == '{' || raw[0] == '[') {
+			req.Body = string(raw[:len(raw)-1])
+		} else {
+			req.Body = "{"
+		}
+		g.add(KindMalformedJSON, "body", "truncated", req)
+	}
+
+	if mt.Schema != nil && mt.Schema.Value != nil {
+		g.walk(mt.Schema.Value, nil, 0)
+	}
+}
+
+// walk emits mutations for the value at path and recurses into object
+// properties and the first array item.
+func (g *gen) walk(s *openapi3.Schema, path []any, depth int) {
+	if s == nil || depth > maxDepth {
+		return
+	}
+	if _, ok := lookup(g.base.Body, path); !ok {
+		return
+	}
+	for _, sub := range s.AllOf {
+		if sub != nil {
+			g.walk(sub.Value, path, depth+1)
+		}
+	}
+
+	target := targetName(path)
+	for _, m := range mutations(s, g.rng) {
+		req := g.baseReq()
+		req.Body = setAt(req.Body, path, m.value)
+		g.add(m.kind, target, m.detail, req)
+	}
+
+	names := make([]string, 0, len(s.Properties))
+	for n := range s.Properties {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range s.Required {
+		if _, ok := lookup(g.base.Body, append(clonePath(path), n)); !ok {
+			continue
+		}
+		req := g.baseReq()
+		req.Body = deleteAt(req.Body, append(clonePath(path), n))
+		g.add(KindMissingRequired, targetName(append(clonePath(path), n)), "", req)
+	}
+	if ap := s.AdditionalProperties.Has; ap != nil && !*ap && len(s.Properties) > 0 {
+		req := g.baseReq()
+		req.Body = setAt(req.Body, append(clonePath(path), "__fuzz"), "unexpected")
+		g.add(KindUnknownProperty, target, "", req)
+	}
+	for _, n := range names {
+		if ref := s.Properties[n]; ref != nil && ref.Value != nil && !ref.Value.ReadOnly {
+			g.walk(ref.Value, append(clonePath(path), n), depth+1)
+		}
+	}
+	if s.Items != nil && s.Items.Value != nil {
+		g.walk(s.Items.Value, append(clonePath(path), 0), depth+1)
+	}
+}
+
+func targetName(path []any) string {
+	var sb strings.Builder
+	sb.WriteString("body")
+	for _, p := range path {
+		switch x := p.(type) {
+		case string:
+			sb.WriteString("." + x)
+		case int:
+			sb.WriteString("[" + strconv.Itoa(x) + "]")
+		}
+	}
+	return sb.String()
+}
+
+// ---- Mutations ----
+
+type mutation struct {
+	kind   string
+	detail string
+	value  any
+}
+
+// mutations lists invalid values for s plus valid values on its boundaries.
+func mutations(s *openapi3.Schema, rng *rand.Rand) []mutation {
+	var out []mutation
+	if v, ok := wrongType(s, rng); ok {
+		out = append(out, mutation{KindWrongType, typeOf(v), v})
+	}
+
+	if len(s.Enum) > 0 {
+		out = append(out, mutation{KindBadEnum, "", badEnum(s.Enum, rng)})
+		for i, e := range s.Enum {
+			out = append(out, mutation{KindBoundary, "enum" + strconv.Itoa(i), e})
+		}
+		return out
+	}
+
+	switch {
+	case s.Type.Includes(openapi3.TypeInteger) || s.Type.Includes(openapi3.TypeNumber):
+		out = append(out, numberMutations(s)...)
+	case s.Type.Includes(openapi3.TypeString):
+		out = append(out, stringMutations(s, rng)...)
+	case s.Type.Includes(openapi3.TypeArray):
+		item := sample.FromSchema(itemSchema(s), sample.Request)
+		if s.MinItems > 0 {
+			out = append(out, mutation{KindTooShort, "minItems-1", repeat(item, int(s.MinItems)-1)})
+		}
+		if s.MaxItems != nil {
+			out = append(out, mutation{KindTooLong, "maxItems+1", repeat(item, int(*s.MaxItems)+1)})
+		}
+	}
+	return out
+}
+
+func numberMutations(s *openapi3.Schema) []mutation {
+	var out []mutation
+	integer := s.Type.Includes(openapi3.TypeInteger)
+	step := 1.0
+	if !integer {
+		step = 0.01
+	}
+	num := func(f float64) any {
+		if integer {
+			return int64(f)
+		}
+		return f
+	}
+	if s.Min != nil {
+		lo := *s.Min
+		if s.ExclusiveMin {
+			out = append(out, mutation{KindOutOfRange, "exclusiveMinimum", num(lo)})
+			out = append(out, mutation{KindBoundary, "minimum", num(lo + step)})
+		} else {
+			out = append(out, mutation{KindOutOfRange, "minimum-1", num(lo - step)})
+			out = append(out, mutation{KindBoundary, "minimum", num(lo)})
+		}
+	}
+	if s.Max != nil {
+		hi := *s.Max
+		if s.ExclusiveMax {
+			out = append(out, mutation{KindOutOfRange, "exclusiveMaximum", num(hi)})
+			out = append(out, mutation{KindBoundary, "maximum", num(hi - step)})
+		} else {
+			out = append(out, mutation{KindOutOfRange, "maximum+1", num(hi + step)})
+			out = append(out, mutation{KindBoundary, "maximum", num(hi)})
+		}
+	}
+	return out
+}
+
+var badFormats = map[string]string{
+	"date":      "not-a-date",
+	"date-time": "not-a-date-time",
+	"email":     "not-an-email",
+	"uuid":      "not-a-uuid",
+	"ipv4":      "999.999.999.999",
+	"uri":       "not a uri",
+}
+
+func stringMutations(s *openapi3.Schema, rng *rand.Rand) []mutation {
+	var out []mutation
+	if bad, ok := badFormats[s.Format]; ok {
+		out = append(out, mutation{KindBadFormat, s.Format, bad})
+	}
+	// Length boundaries are only valid if nothing else constrains the value.
+	plain := s.Format == "" && s.Pattern == ""
+	if s.MaxLength != nil {
+		n := int(*s.MaxLength)
+		out = append(out, mutation{KindTooLong, "maxLength+1", randString(rng, n+1)})
+		if plain {
+			out = append(out, mutation{KindBoundary, "maxLength", randString(rng, n)})
+		}
+	}
+	if s.MinLength > 0 {
+		n := int(s.MinLength)
+		out = append(out, mutation{KindTooShort, "minLength-1", randString(rng, n-1)})
+		if plain {
+			out = append(out, mutation{KindBoundary, "minLength", randString(rng, n)})
+		}
+	}
+	return out
+}
+
+// wrongType returns a value of a different JSON type than s allows.
+func wrongType(s *openapi3.Schema, rng *rand.Rand) (any, bool) {
+	var pool []any
+	switch {
+	case s.Type.Includes(openapi3.TypeObject):
+		pool = []any{"not-an-object", []any{}, int64(42)}
+	case s.Type.Includes(openapi3.TypeArray):
+		pool = []any{"not-an-array", map[string]any{}, int64(42)}
+	case s.Type.Includes(openapi3.TypeString):
+		pool = []any{int64(12345), true, []any{}}
+	case s.Type.Includes(openapi3.TypeInteger):
+		pool = []any{"not-a-number", 1.5, true}
+	case s.Type.Includes(openapi3.TypeNumber):
+		pool = []any{"not-a-number", true}
+	case s.Type.Includes(openapi3.TypeBoolean):
+		pool = []any{"yes", int64(1)}
+	default:
+		return nil, false
+	}
+	return pool[rng.IntN(len(pool))], true
+}
+
+func typeOf(v any) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int64:
+		return "integer"
+	case float64:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func badEnum(enum []any, rng *rand.Rand) any {
+	for {
+		v := "fuzz-" + randString(rng, 6)
+		found := false
+		for _, e := range enum {
+			if fmt.Sprint(e) == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return v
+		}
+	}
+}
+
+const letters = "abcdefghijklmnopqrstuvwxyz"
+
+func randString(rng *rand.Rand, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[rng.IntN(len(letters))]
+	}
+	return string(b)
+}
+
+func itemSchema(s *openapi3.Schema) *openapi3.Schema {
+	if s.Items != nil {
+		return s.Items.Value
+	}
+	return nil
+}
+
+func repeat(v any, n int) []any {
+	out := make([]any, 0, max(n, 0))
+	for i := 0; i < n; i++ {
+		out = append(out, deepCopy(v))
+	}
+	return out
+}
+
+// ---- JSON value paths (string = object key, int = array index) ----
+
+func clonePath(p []any) []any {
+	return append([]any(nil), p...)
+}
+
+func lookup(v any, path []any) (any, bool) {
+	for _, p := range path {
+		switch k := p.(type) {
+		case string:
+			m, ok := v.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			if v, ok = m[k]; !ok {
+				return nil, false
+			}
+		case int:
+			a, ok := v.([]any)
+			if !ok || k >= len(a) {
+				return nil, false
+			}
+			v = a[k]
+		}
+	}
+	return v, true
+}
+
+// setAt sets the value at path (creating the final object key if missing)
+// and returns the possibly replaced root.
+func setAt(root any, path []any, val any) any {
+	if len(path) == 0 {
+		return rootBody(val)
+	}
+	parent, ok := lookup(root, path[:len(path)-1])
+	if !ok {
+		return root
+	}
+	switch k := path[len(path)-1].(type) {
+	case string:
+		if m, ok := parent.(map[string]any); ok {
+			m[k] = val
+		}
+	case int:
+		if a, ok := parent.([]any); ok && k < len(a) {
+			a[k] = val
+		}
+	}
+	return root
+}
+
+// rootBody JSON-encodes a string body: the executor sends string bodies
+// as they are, which only malformed-json cases want.
+func rootBody(v any) any {
+	if s, ok := v.(string); ok {
+		b, _ := json.Marshal(s)
+		return string(b)
+	}
+	return v
+}
+
+func deleteAt(root any, path []any) any {
+	if len(path) == 0 {
+		return nil
+	}
+	parent, ok := lookup(root, path[:len(path)-1])
+	if !ok {
+		return root
+	}
+	if k, ok := path[len(path)-1].(string); ok {
+		if m, ok := parent.(map[string]any); ok {
+			delete(m, k)
+		}
+	}
+	return root
+}
+
+func deepCopy(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, e := range x {
+			out[k] = deepCopy(e)
+		}
+		return out
+	case []any:
+		out := make([]any, len(x))
+		for i, e := range x {
+			out[i] = deepCopy(e)
+		}
+		return out
+	}
+	return v
+}
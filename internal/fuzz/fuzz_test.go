@@ -0,0 +1,226 @@
+package fuzz_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"sea-qa/internal/contract"
+	"sea-qa/internal/executor"
+	"sea-qa/internal/fuzz"
+)
+
+const petsSpec = `
+openapi: 3.0.3
+info: { title: Pets, version: "1" }
+paths:
+  /pets:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name, age]
+              additionalProperties: false
+              properties:
+                name: { type: string, minLength: 1, maxLength: 10 }
+                age: { type: integer, minimum: 0, maximum: 30 }
+                kind: { type: string, enum: [dog, cat] }
+      responses:
+        "201": { description: created }
+        "400": { description: bad }
+  /pets/{petId}:
+    get:
+      parameters:
+        - { name: petId, in: path, required: true, schema: { type: integer, example: 7 } }
+      responses:
+        "200": { description: ok }
+        "4XX": { description: bad }
+`
+
+func loadPlan(t *testing.T, seed int64, max int) *fuzz.Plan {
+	t.Helper()
+	v, err := contract.LoadFromBytes([]byte(petsSpec))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	return fuzz.Generate(v.Doc(), fuzz.Options{Seed: seed, MaxCases: max})
+}
+
+func TestGenerate_CoversKindsAndIsReproducible(t *testing.T) {
+	p := loadPlan(t, 42, 0)
+	if len(p.Operations) != 2 {
+		t.Fatalf("operations = %d, want 2", len(p.Operations))
+	}
+	post := p.Operations[0]
+	if post.Method != "POST" {
+		t.Fatalf("first operation = %s %s", post.Method, post.Path)
+	}
+	ids := map[string]fuzz.Case{}
+	for _, c := range post.Cases {
+		ids[c.ID] = c
+	}
+	for _, id := range []string{
+		"body:missing-required",
+		"body:malformed-json:truncated",
+		"body.name:missing-required",
+		"body.name:too-long:maxLength+1",
+		"body.name:boundary:maxLength",
+		"body.age:out-of-range:maximum+1",
+		"body.age:boundary:minimum",
+		"body.kind:bad-enum",
+		"body:unknown-property",
+	} {
+		if _, ok := ids[id]; !ok {
+			t.Errorf("missing case %s", id)
+		}
+	}
+	if c := ids["body.age:out-of-range:maximum+1"]; c.Request.Body.(map[string]any)["age"] != int64(31) {
+		t.Errorf("age out of range body = %v", c.Request.Body)
+	}
+	if c := ids["body.name:too-long:maxLength+1"]; len(c.Request.Body.(map[string]any)["name"].(string)) != 11 {
+		t.Errorf("too-long body = %v", c.Request.Body)
+	}
+
+	again := loadPlan(t, 42, 0)
+	a, _ := json.Marshal(p.Operations[0].Cases)
+	b, _ := json.Marshal(again.Operations[0].Cases)
+	if diff := cmp.Diff(string(a), string(b)); diff != "" {
+		t.Fatalf("same seed produced different cases (-a +b):\n%s", diff)
+	}
+
+	limited := loadPlan(t, 42, 3)
+	if n := len(limited.Operations[0].Cases); n != 3 {
+		t.Fatalf("max cases = %d, want 3", n)
+	}
+}
+
+func TestEvaluate_FlagsServerErrorsAndAcceptedInput(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			// rejects bad ids but with an undocumented 5xx
+			if strings.HasSuffix(r.URL.Path, "/7") {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if _, ok := body["name"]; !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated) // accepts everything else
+	}))
+	defer srv.Close()
+
+	p := loadPlan(t, 1, 0)
+	res, err := executor.NewWithVars(map[string]string{"BASE_URL": srv.URL}).RunSuite(context.Background(), p.Suite("fuzz"))
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	rep := fuzz.Evaluate(p, res)
+	if rep.Passed || res.Passed {
+		t.Fatalf("expected failures")
+	}
+	if rep.Seed != 1 {
+		t.Fatalf("seed = %d", rep.Seed)
+	}
+
+	byID := map[string]fuzz.CaseResult{}
+	for _, op := range rep.Operations {
+		for _, c := range op.Results {
+			byID[op.Method+" "+c.ID] = c
+		}
+	}
+	check := func(id string, passed bool, errPart string) {
+		t.Helper()
+		c, ok := byID[id]
+		if !ok {
+			t.Fatalf("missing result %s", id)
+		}
+		if c.Passed != passed || !strings.Contains(c.Error, errPart) {
+			t.Errorf("%s: passed=%v err=%q, want passed=%v err~%q", id, c.Passed, c.Error, passed, errPart)
+		}
+	}
+	check("POST body.name:missing-required", true, "")
+	check("POST body:malformed-json:truncated", true, "")
+	check("POST body.age:boundary:maximum", true, "")
+	check("POST body.age:out-of-range:maximum+1", false, "accepted invalid request")
+	wrongType := 0
+	for id, c := range byID {
+		if !strings.HasPrefix(id, "GET path.petId:wrong-type") {
+			continue
+		}
+		wrongType++
+		if c.Passed || !strings.Contains(c.Error, "server error 500") {
+			t.Errorf("%s: passed=%v err=%q, want server error", id, c.Passed, c.Error)
+		}
+	}
+	if wrongType == 0 {
+		t.Errorf("no wrong-type case for path.petId")
+	}
+
+	// failures are mirrored into the executor results
+	for _, st := range res.Scenarios[0].Steps {
+		if st.Name == "body.age:out-of-range:maximum+1" && st.Passed {
+			t.Errorf("step result not marked failed")
+		}
+	}
+}
+
+func TestGenerate_RootValuesAreJSON(t *testing.T) {
+	const spec = `
+openapi: 3.0.3
+info: { title: Tags, version: "1" }
+paths:
+  /tags:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: { type: string, enum: [red, blue], maxLength: 4 }
+      responses:
+        "201": { description: created }
+  /notes:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: { type: object, properties: { text: { type: string } } }
+      responses:
+        "201": { description: created }
+`
+	v, err := contract.LoadFromBytes([]byte(spec))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	n := 0
+	for _, op := range fuzz.Generate(v.Doc(), fuzz.Options{Seed: 1}).Operations {
+		for _, c := range op.Cases {
+			s, ok := c.Request.Body.(string)
+			if !ok || c.Kind == fuzz.KindMalformedJSON {
+				continue
+			}
+			n++
+			if !json.Valid([]byte(s)) {
+				t.Errorf("%s %s: body %q is not JSON", op.Path, c.ID, s)
+			}
+		}
+	}
+	if n == 0 {
+		t.Fatal("no string-bodied cases generated")
+	}
+}
@@ -0,0 +1,121 @@
+package fuzz
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"sea-qa/internal/executor"
+)
+
+type Report struct {
+	Seed       int64             `json:"seed"`
+	Passed     bool              `json:"passed"`
+	Total      int               `json:"total"`
+	Failed     int               `json:"failed"`
+	Operations []OperationReport `json:"operations"`
+}
+
+type OperationReport struct {
+	Method  string       `json:"method"`
+	Path    string       `json:"path"`
+	Total   int          `json:"total"`
+	Failed  int          `json:"failed"`
+	Results []CaseResult `json:"results"`
+}
+
+type CaseResult struct {
+	Case
+	Status int    `json:"status"`
+	Passed bool   `json:"passed"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Evaluate judges each response: it must be below 500 and documented for the
+// operation (exact code, NXX range or default), and invalid cases must be
+// rejected with a 4xx. Failing verdicts are also written back into res so the
+// regular JSON/JUnit/HTML reports show them.
+func Evaluate(p *Plan, res *executor.SuiteResult) *Report {
+	rep := &Report{Seed: p.Seed, Passed: true}
+	for i, op := range p.Operations {
+		or := OperationReport{Method: op.Method, Path: op.Path}
+		var sc *executor.ScenarioResult
+		if i < len(res.Scenarios) {
+			sc = &res.Scenarios[i]
+		}
+		for j, c := range op.Cases {
+			cr := CaseResult{Case: c, Passed: true}
+			var st *executor.StepResult
+			if sc != nil && j < len(sc.Steps) {
+				st = &sc.Steps[j]
+			}
+			switch {
+			case st == nil:
+				cr.Passed, cr.Error = false, "not executed"
+			case len(st.Errors) > 0:
+				cr.Passed, cr.Error = false, strings.Join(st.Errors, "; ")
+				cr.Status = st.StatusCode
+			default:
+				cr.Status = st.StatusCode
+				if msg := verdict(op.Op, c, st.StatusCode); msg != "" {
+					cr.Passed, cr.Error = false, msg
+					st.Passed = false
+					st.Errors = append(st.Errors, "fuzz: "+msg)
+				}
+			}
+			if st != nil {
+				st.Name = c.ID
+			}
+			if !cr.Passed {
+				or.Failed++
+			}
+			or.Results = append(or.Results, cr)
+		}
+		or.Total = len(or.Results)
+		if sc != nil {
+			sc.Passed = or.Failed == 0
+		}
+		rep.Total += or.Total
+		rep.Failed += or.Failed
+		rep.Operations = append(rep.Operations, or)
+	}
+	rep.Passed = rep.Failed == 0
+	res.Passed = rep.Passed
+	return rep
+}
+
+func verdict(op *openapi3.Operation, c Case, status int) string {
+	switch {
+	case status >= 500:
+		return fmt.Sprintf("server error %d", status)
+	case !c.Valid && (status < 400 || status >= 500):
+		return fmt.Sprintf("accepted invalid request (%s) with status %d", c.Kind, status)
+	case !documented(op, status):
+		return fmt.Sprintf("status %d is not documented for this operation", status)
+	}
+	return ""
+}
+
+func documented(op *openapi3.Operation, status int) bool {
+	if op.Responses == nil {
+		return false
+	}
+	code := strconv.Itoa(status)
+	rng := code[:1] + "XX"
+	for _, k := range []string{code, rng, strings.ToLower(rng)} {
+		if op.Responses.Value(k) != nil {
+			return true
+		}
+	}
+	return op.Responses.Default() != nil
+}
+
+func WriteJSON(w io.Writer, rep *Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rep)
+}
@@ -0,0 +1,156 @@
+package generate
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"sea-qa/internal/ir"
+)
+
+// Skipped records an operation FromExamples could not build a request for.
+type Skipped struct {
+	Method string
+	Path   string
+	Reason string
+}
+
+// FromExamples emits one scenario per documented request example. An
+// operation is only used when its request is fully determined by the spec:
+// every required parameter has an example, and a required body has at least
+// one example. Each step expects the contract to hold and the status of the
+// response carrying an example of the same name (else the lowest 2xx).
+func FromExamples(doc *openapi3.T, opt Options) (*ir.TestSuite, []Skipped) {
+	suite := newSuite(doc, opt, "examples")
+	var skipped []Skipped
+	for _, o := range Operations(doc) {
+		if p := missingParamExample(o); p != nil {
+			skipped = append(skipped, Skipped{o.Method, o.Path, "no example for " + p.In + " parameter " + p.Name})
+			continue
+		}
+		base := BuildRequest(o, baseVar(opt))
+
+		ct, examples, required := bodyExamples(o.Op)
+		if len(examples) == 0 {
+			if required {
+				skipped = append(skipped, Skipped{o.Method, o.Path, "no request body example"})
+				continue
+			}
+			examples = []namedExample{{}}
+		}
+
+		for _, ex := range examples {
+			req := base
+			if ex.value != nil {
+				req.Headers = map[string]string{}
+				for k, v := range base.Headers {
+					req.Headers[k] = v
+				}
+				req.Body = ex.value
+				setHeader(&req, "Content-Type", ct)
+			}
+			name := scenarioName(o)
+			if ex.name != "" {
+				name += " [example: " + ex.name + "]"
+			}
+			status, ok := exampleStatus(o.Op, ex.name)
+			if !ok {
+				status = SuccessStatus(o.Op)
+			}
+			suite.Scenarios = append(suite.Scenarios, ir.Scenario{
+				Name: name,
+				Tags: append([]string{"examples"}, o.Op.Tags...),
+				Steps: []ir.Step{{
+					Name:    ex.name,
+					Request: req,
+					Expect: []ir.Expectation{
+						{Type: ir.ExpectStatus, Value: status},
+						{Type: ir.ExpectContract, Value: true},
+					},
+				}},
+			})
+		}
+	}
+	return suite, skipped
+}
+
+type namedExample struct {
+	name  string
+	value any
+}
+
+// bodyExamples lists the media type's examples: `example` (named "example"),
+// then `examples` by name, then the schema's own example (named "schema").
+func bodyExamples(op *openapi3.Operation) (string, []namedExample, bool) {
+	if op.RequestBody == nil || op.RequestBody.Value == nil {
+		return "", nil, false
+	}
+	rb := op.RequestBody.Value
+	ct, mt := PickMediaType(rb.Content)
+	if mt == nil {
+		return "", nil, rb.Required
+	}
+	var out []namedExample
+	if mt.Example != nil {
+		out = append(out, namedExample{"example", mt.Example})
+	}
+	names := make([]string, 0, len(mt.Examples))
+	for n := range mt.Examples {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if e := mt.Examples[n]; e != nil && e.Value != nil && e.Value.Value != nil {
+			out = append(out, namedExample{n, e.Value.Value})
+		}
+	}
+	if len(out) == 0 && mt.Schema != nil && mt.Schema.Value != nil && mt.Schema.Value.Example != nil {
+		out = append(out, namedExample{"schema", mt.Schema.Value.Example})
+	}
+	return ct, out, rb.Required
+}
+
+func missingParamExample(o Operation) *openapi3.Parameter {
+	for _, p := range Parameters(o) {
+		if !p.Required {
+			continue
+		}
+		if p.Example != nil || firstExample(p.Examples) != nil {
+			continue
+		}
+		if p.Schema != nil && p.Schema.Value != nil && (p.Schema.Value.Example != nil || p.Schema.Value.Default != nil) {
+			continue
+		}
+		return p
+	}
+	return nil
+}
+
+// exampleStatus finds the response that documents an example called name.
+func exampleStatus(op *openapi3.Operation, name string) (int, bool) {
+	if name == "" || op.Responses == nil {
+		return 0, false
+	}
+	codes := make([]string, 0, op.Responses.Len())
+	for c := range op.Responses.Map() {
+		codes = append(codes, c)
+	}
+	sort.Strings(codes)
+	for _, c := range codes {
+		n, err := strconv.Atoi(c)
+		if err != nil {
+			continue
+		}
+		rr := op.Responses.Value(c)
+		if rr == nil || rr.Value == nil {
+			continue
+		}
+		for _, mt := range rr.Value.Content {
+			if mt != nil && mt.Examples[name] != nil {
+				return n, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,114 @@
+package generate_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sea-qa/internal/contract"
+	"sea-qa/internal/executor"
+	"sea-qa/internal/generate"
+)
+
+const examplesSpec = `
+openapi: 3.0.3
+info: { title: Pets, version: "1" }
+paths:
+  /pets:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: { type: string }
+            examples:
+              rex: { value: { name: Rex } }
+              invalid: { value: { name: "" } }
+      responses:
+        "201":
+          description: created
+          content:
+            application/json:
+              schema: { type: object, required: [id], properties: { id: { type: integer } } }
+        "400":
+          description: bad
+          content:
+            application/json:
+              schema: { type: object, properties: { error: { type: string } } }
+              examples:
+                invalid: { value: { error: name is empty } }
+  /pets/{petId}:
+    get:
+      parameters:
+        - { name: petId, in: path, required: true, schema: { type: integer } }
+      responses:
+        "200": { description: ok }
+  /health:
+    get:
+      responses:
+        "200": { description: ok }
+`
+
+func TestFromExamples_RunsAgainstContract(t *testing.T) {
+	v, err := contract.LoadFromBytes([]byte(examplesSpec))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	suite, skipped := generate.FromExamples(v.Doc(), generate.Options{})
+
+	if len(skipped) != 1 || skipped[0].Path != "/pets/{petId}" {
+		t.Fatalf("skipped = %+v, want only /pets/{petId}", skipped)
+	}
+	names := []string{}
+	for _, sc := range suite.Scenarios {
+		names = append(names, sc.Name)
+	}
+	want := []string{"GET /health", "POST /pets [example: invalid]", "POST /pets [example: rex]"}
+	if len(names) != len(want) {
+		t.Fatalf("scenarios = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("scenarios = %v, want %v", names, want)
+		}
+	}
+	if got := suite.Scenarios[1].Steps[0].Expect[0].Value; got != 400 {
+		t.Fatalf("invalid example expects status %v, want 400", got)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/health" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		var in map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		if in["name"] == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"name is empty"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer srv.Close()
+
+	r := executor.NewWithVars(map[string]string{"BASE_URL": srv.URL}).WithContract(v)
+	res, err := r.RunSuite(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if !res.Passed {
+		t.Fatalf("expected pass: %+v", res.Scenarios)
+	}
+	if !r.Covered()["POST"]["/pets"] || !r.Covered()["GET"]["/health"] {
+		t.Fatalf("coverage = %v", r.Covered())
+	}
+}
@@ -0,0 +1,295 @@
+package generate
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"sea-qa/internal/ir"
+	"sea-qa/internal/sample"
+)
+
+type Options struct {
+	Name    string // suite name; defaults to "<info.title> (generated)"
+	OpenAPI string // written to suite.openapi so contract checks work out of the box
+	BaseVar string // variable holding the base URL; defaults to BASE_URL
+}
+
+// Operation is a spec operation in stable (path, method) order.
+type Operation struct {
+	Method string
+	Path   string
+	Item   *openapi3.PathItem
+	Op     *openapi3.Operation
+}
+
+var methodOrder = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS", "TRACE"}
+
+// Operations lists every operation in doc sorted by path, then method.
+func Operations(doc *openapi3.T) []Operation {
+	var out []Operation
+	if doc == nil || doc.Paths == nil {
+		return out
+	}
+	paths := make([]string, 0, doc.Paths.Len())
+	for p := range doc.Paths.Map() {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		pi := doc.Paths.Value(p)
+		if pi == nil {
+			continue
+		}
+		for _, m := range methodOrder {
+			if op := pi.GetOperation(m); op != nil {
+				out = append(out, Operation{Method: m, Path: p, Item: pi, Op: op})
+			}
+		}
+	}
+	return out
+}
+
+// FromDoc emits a starter suite: one scenario per operation with a request
+// built from examples/defaults and status + contract expectations.
+func FromDoc(doc *openapi3.T, opt Options) *ir.TestSuite {
+	suite := newSuite(doc, opt, "generated")
+	for _, o := range Operations(doc) {
+		req := BuildRequest(o, baseVar(opt))
+		if body, ct := RequestBody(o.Op); body != nil {
+			req.Body = body
+			setHeader(&req, "Content-Type", ct)
+		}
+		suite.Scenarios = append(suite.Scenarios, ir.Scenario{
+			Name: scenarioName(o),
+			Tags: append([]string{"generated"}, o.Op.Tags...),
+			Steps: []ir.Step{{
+				Request: req,
+				Expect: []ir.Expectation{
+					{Type: ir.ExpectStatus, Value: SuccessStatus(o.Op)},
+					{Type: ir.ExpectContract, Value: true},
+				},
+			}},
+		})
+	}
+	return suite
+}
+
+func newSuite(doc *openapi3.T, opt Options, kind string) *ir.TestSuite {
+	name := opt.Name
+	if name == "" {
+		name = kind
+		if doc.Info != nil && doc.Info.Title != "" {
+			name = doc.Info.Title + " (" + kind + ")"
+		}
+	}
+	return &ir.TestSuite{Name: name, OpenAPI: opt.OpenAPI}
+}
+
+func baseVar(opt Options) string {
+	if opt.BaseVar != "" {
+		return opt.BaseVar
+	}
+	return "BASE_URL"
+}
+
+func scenarioName(o Operation) string {
+	name := o.Method + " " + o.Path
+	if o.Op.OperationID != "" {
+		name += " (" + o.Op.OperationID + ")"
+	}
+	return name
+}
+
+// BuildRequest renders the URL with path params as ${name|example} and fills
+// required query/header parameters with sample values. No body is set.
+func BuildRequest(o Operation, baseVar string) ir.Request {
+	path := o.Path
+	query := url.Values{}
+	req := ir.Request{Method: o.Method}
+
+	for _, p := range Parameters(o) {
+		val := ParamValue(p)
+		switch p.In {
+		case openapi3.ParameterInPath:
+			ph := "${" + p.Name
+			if val != "" {
+				ph += "|" + val
+			}
+			ph += "}"
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", ph)
+		case openapi3.ParameterInQuery:
+			if p.Required {
+				query.Set(p.Name, val)
+			}
+		case openapi3.ParameterInHeader:
+			if p.Required {
+				setHeader(&req, p.Name, val)
+			}
+		}
+	}
+
+	req.URL = "${" + baseVar + "}" + path
+	if len(query) > 0 {
+		req.URL += "?" + query.Encode()
+	}
+	if acceptsJSON(o.Op) {
+		setHeader(&req, "Accept", "application/json")
+	}
+	return req
+}
+
+// Parameters merges path-item and operation parameters (operation wins).
+func Parameters(o Operation) []*openapi3.Parameter {
+	type key struct{ in, name string }
+	seen := map[key]int{}
+	var out []*openapi3.Parameter
+	add := func(ps openapi3.Parameters) {
+		for _, pr := range ps {
+			if pr == nil || pr.Value == nil {
+				continue
+			}
+			k := key{pr.Value.In, pr.Value.Name}
+			if i, ok := seen[k]; ok {
+				out[i] = pr.Value
+				continue
+			}
+			seen[k] = len(out)
+			out = append(out, pr.Value)
+		}
+	}
+	if o.Item != nil {
+		add(o.Item.Parameters)
+	}
+	add(o.Op.Parameters)
+	return out
+}
+
+// ParamValue renders the parameter example (or a schema sample) as a string.
+func ParamValue(p *openapi3.Parameter) string {
+	var v any
+	switch {
+	case p.Example != nil:
+		v = p.Example
+	case firstExample(p.Examples) != nil:
+		v = firstExample(p.Examples)
+	case p.Schema != nil && p.Schema.Value != nil:
+		v = sample.FromSchema(p.Schema.Value, sample.Request)
+	}
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case []any:
+		parts := make([]string, 0, len(x))
+		for _, e := range x {
+			parts = append(parts, fmt.Sprint(e))
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+// firstExample returns the value of the first named example (by name).
+func firstExample(ex openapi3.Examples) any {
+	names := make([]string, 0, len(ex))
+	for n := range ex {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if e := ex[n]; e != nil && e.Value != nil && e.Value.Value != nil {
+			return e.Value.Value
+		}
+	}
+	return nil
+}
+
+// RequestBody picks the JSON media type if present, else the first one.
+func RequestBody(op *openapi3.Operation) (any, string) {
+	if op.RequestBody == nil || op.RequestBody.Value == nil {
+		return nil, ""
+	}
+	ct, mt := PickMediaType(op.RequestBody.Value.Content)
+	if mt == nil {
+		return nil, ""
+	}
+	return sample.FromMediaType(mt, sample.Request), ct
+}
+
+// PickMediaType prefers application/json, then any *json type, then the first
+// media type by name.
+func PickMediaType(c openapi3.Content) (string, *openapi3.MediaType) {
+	if len(c) == 0 {
+		return "", nil
+	}
+	if mt := c.Get("application/json"); mt != nil {
+		return "application/json", mt
+	}
+	names := make([]string, 0, len(c))
+	for n := range c {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if strings.Contains(n, "json") {
+			return n, c[n]
+		}
+	}
+	return names[0], c[names[0]]
+}
+
+func acceptsJSON(op *openapi3.Operation) bool {
+	if op.Responses == nil {
+		return false
+	}
+	for _, rr := range op.Responses.Map() {
+		if rr == nil || rr.Value == nil {
+			continue
+		}
+		for ct := range rr.Value.Content {
+			if strings.Contains(ct, "json") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SuccessStatus returns the lowest documented 2xx status (200 if none).
+func SuccessStatus(op *openapi3.Operation) int {
+	best := 0
+	if op.Responses != nil {
+		for code := range op.Responses.Map() {
+			n, err := strconv.Atoi(code)
+			if err != nil {
+				if strings.EqualFold(code, "2XX") && best == 0 {
+					best = 200
+				}
+				continue
+			}
+			if n >= 200 && n < 300 && (best == 0 || n < best) {
+				best = n
+			}
+		}
+	}
+	if best == 0 {
+		return 200
+	}
+	return best
+}
+
+func setHeader(req *ir.Request, k, v string) {
+	if req.Headers == nil {
+		req.Headers = map[string]string{}
+	}
+	req.Headers[k] = v
+}
@@ -0,0 +1,119 @@
+package generate_test
+
+import (
+	"strings"
+	"testing"
+
+	"sea-qa/internal/contract"
+	"sea-qa/internal/generate"
+	"sea-qa/internal/ir"
+	"sea-qa/internal/parser"
+)
+
+const petsSpec = `
+openapi: 3.0.3
+info: { title: Pets, version: "1" }
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      parameters:
+        - { name: limit, in: query, required: true, schema: { type: integer, minimum: 5 } }
+        - { name: sort, in: query, schema: { type: string } }
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: { type: array, items: { $ref: "#/components/schemas/Pet" } }
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: { $ref: "#/components/schemas/Pet" }
+      responses:
+        "201": { description: created }
+        "400": { description: bad }
+  /pets/{petId}:
+    parameters:
+      - { name: petId, in: path, required: true, schema: { type: integer, example: 7 } }
+    delete:
+      parameters:
+        - { name: X-Tenant, in: header, required: true, schema: { type: string, default: acme } }
+      responses:
+        "204": { description: gone }
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        id: { type: integer, readOnly: true }
+        name: { type: string, example: Rex }
+        kind: { type: string, enum: [dog, cat] }
+        born: { type: string, format: date }
+`
+
+func TestFromDoc_RoundTripsThroughParser(t *testing.T) {
+	v, err := contract.LoadFromBytes([]byte(petsSpec))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	suite := generate.FromDoc(v.Doc(), generate.Options{OpenAPI: "pets.yaml"})
+
+	data, err := parser.Marshal(suite)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	parsed, err := parser.New().ParseBytes(data)
+	if err != nil {
+		t.Fatalf("generated suite does not parse: %v\n%s", err, data)
+	}
+	if parsed.Name != "Pets (generated)" || parsed.OpenAPI != "pets.yaml" {
+		t.Fatalf("suite header = %q %q", parsed.Name, parsed.OpenAPI)
+	}
+	if len(parsed.Scenarios) != 3 {
+		t.Fatalf("scenarios = %d, want 3", len(parsed.Scenarios))
+	}
+
+	byName := map[string]ir.Step{}
+	for _, sc := range parsed.Scenarios {
+		byName[sc.Name] = sc.Steps[0]
+	}
+
+	list := byName["GET /pets (listPets)"]
+	if list.Request.URL != "${BASE_URL}/pets?limit=5" {
+		t.Errorf("list url = %q", list.Request.URL)
+	}
+
+	create := byName["POST /pets"]
+	body, ok := create.Request.Body.(map[string]any)
+	if !ok {
+		t.Fatalf("create body = %#v", create.Request.Body)
+	}
+	if body["name"] != "Rex" || body["kind"] != "dog" || body["born"] != "2024-01-01" {
+		t.Errorf("create body = %v", body)
+	}
+	if _, has := body["id"]; has {
+		t.Errorf("readOnly id must not be sent: %v", body)
+	}
+	if create.Request.Headers["Content-Type"] != "application/json" {
+		t.Errorf("content-type = %q", create.Request.Headers["Content-Type"])
+	}
+	if got := create.Expect[0]; got.Type != ir.ExpectStatus || got.Value != 201 {
+		t.Errorf("create status expectation = %+v", got)
+	}
+
+	del := byName["DELETE /pets/{petId}"]
+	if del.Request.URL != "${BASE_URL}/pets/${petId|7}" {
+		t.Errorf("delete url = %q", del.Request.URL)
+	}
+	if del.Request.Headers["X-Tenant"] != "acme" {
+		t.Errorf("delete headers = %v", del.Request.Headers)
+	}
+	if !strings.Contains(string(data), "type: contract") {
+		t.Errorf("expected contract expectations in output")
+	}
+}
@@ -0,0 +1,319 @@
+package har
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"sea-qa/internal/capture"
+	"sea-qa/internal/executor"
+	"sea-qa/internal/ir"
+)
+
+// ---- HAR 1.2 model (the subset we read and write) ----
+
+type File struct {
+	Log Log `json:"log"`
+}
+
+type Log struct {
+	Version string  `json:"version"`
+	Creator Creator `json:"creator"`
+	Entries []Entry `json:"entries"`
+}
+
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type Entry struct {
+	StartedDateTime string   `json:"startedDateTime"`
+	Time            float64  `json:"time"`
+	Request         Request  `json:"request"`
+	Response        Response `json:"response"`
+	Cache           struct{} `json:"cache"`
+	Timings         Timings  `json:"timings"`
+	Comment         string   `json:"comment,omitempty"`
+	ResourceType    string   `json:"_resourceType,omitempty"` // Chrome/Firefox extension
+}
+
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []NameValue `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	PostData    *PostData   `json:"postData,omitempty"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type Response struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []NameValue `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	Content     Content     `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type Content struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type Timings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+func Load(path string) (*File, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read har: %w", err)
+	}
+	var f File
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("parse har: %w", err)
+	}
+	return &f, nil
+}
+
+func Write(w io.Writer, f *File) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f)
+}
+
+// ---- Import ----
+
+type ImportOptions struct {
+	Name    string // suite name; defaults to "har import"
+	BaseVar string // variable replacing the main origin; defaults to BASE_URL
+	All     bool   // keep static assets (scripts, styles, images, fonts, documents)
+}
+
+// ToSuite turns entries into one scenario with a step per request and a
+// status expectation each. The most frequent origin becomes ${BaseVar};
+// cookies and transport/browser noise headers are dropped.
+func ToSuite(f *File, opt ImportOptions) *ir.TestSuite {
+	name := opt.Name
+	if name == "" {
+		name = "har import"
+	}
+	baseVar := opt.BaseVar
+	if baseVar == "" {
+		baseVar = "BASE_URL"
+	}
+
+	var entries []Entry
+	for _, e := range f.Log.Entries {
+		if opt.All || !static(e) {
+			entries = append(entries, e)
+		}
+	}
+	origin := mainOrigin(entries)
+
+	sc := ir.Scenario{Name: "har session", Tags: []string{"har"}}
+	for i, e := range entries {
+		u := e.Request.URL
+		if origin != "" && strings.HasPrefix(u, origin) {
+			u = "${" + baseVar + "}" + strings.TrimPrefix(u, origin)
+		}
+		req := ir.Request{Method: strings.ToUpper(e.Request.Method), URL: u}
+		for _, h := range e.Request.Headers {
+			if capture.KeepHeader(h.Name) {
+				if req.Headers == nil {
+					req.Headers = map[string]string{}
+				}
+				v := h.Value
+				if capture.Credential(h.Name) {
+					v = capture.CredentialRef(h.Name, v)
+				}
+				req.Headers[http.CanonicalHeaderKey(h.Name)] = v
+			}
+		}
+		if e.Request.PostData != nil && e.Request.PostData.Text != "" {
+			req.Body = capture.ParseBody(e.Request.PostData.Text)
+			if req.Headers["Content-Type"] == "" && e.Request.PostData.MimeType != "" {
+				if req.Headers == nil {
+					req.Headers = map[string]string{}
+				}
+				req.Headers["Content-Type"] = e.Request.PostData.MimeType
+			}
+		}
+		sc.Steps = append(sc.Steps, ir.Step{
+			Name:    fmt.Sprintf("%d %s %s", i+1, req.Method, pathOf(e.Request.URL)),
+			Request: req,
+			Expect:  []ir.Expectation{{Type: ir.ExpectStatus, Value: e.Response.Status}},
+		})
+	}
+	return &ir.TestSuite{Name: name, Scenarios: []ir.Scenario{sc}}
+}
+
+var staticTypes = map[string]bool{
+	"document": true, "stylesheet": true, "script": true, "image": true,
+	"font": true, "media": true, "manifest": true, "ping": true,
+}
+
+func static(e Entry) bool {
+	if e.ResourceType != "" {
+		return staticTypes[strings.ToLower(e.ResourceType)]
+	}
+	mt := strings.ToLower(e.Response.Content.MimeType)
+	return strings.HasPrefix(mt, "image/") || strings.HasPrefix(mt, "font/") ||
+		strings.HasPrefix(mt, "text/css") || strings.HasPrefix(mt, "text/html") ||
+		strings.Contains(mt, "javascript")
+}
+
+func mainOrigin(entries []Entry) string {
+	count := map[string]int{}
+	for _, e := range entries {
+		if u, err := url.Parse(e.Request.URL); err == nil && u.Host != "" {
+			count[u.Scheme+"://"+u.Host]++
+		}
+	}
+	best := ""
+	for o, n := range count {
+		if n > count[best] || (n == count[best] && o < best) {
+			best = o
+		}
+	}
+	return best
+}
+
+func pathOf(raw string) string {
+	if u, err := url.Parse(raw); err == nil {
+		return u.RequestURI()
+	}
+	return raw
+}
+
+// ---- Export ----
+
+// FromResults renders every executed step as an entry; the entry comment
+// names the scenario and step.
+func FromResults(res *executor.SuiteResult, creatorVersion string) *File {
+	f := &File{Log: Log{
+		Version: "1.2",
+		Creator: Creator{Name: "sea-qa", Version: creatorVersion},
+		Entries: []Entry{},
+	}}
+	for _, sc := range res.Scenarios {
+		for i, st := range sc.Steps {
+			if st.Method == "" || st.URL == "" {
+				continue
+			}
+			f.Log.Entries = append(f.Log.Entries, entry(sc.Name, i, st))
+		}
+	}
+	return f
+}
+
+func entry(scenario string, i int, st executor.StepResult) Entry {
+	started := st.StartedAt
+	if started.IsZero() {
+		started = time.Now()
+	}
+	comment := fmt.Sprintf("%s / step %d", scenario, i+1)
+	if st.Name != "" {
+		comment += " (" + st.Name + ")"
+	}
+	e := Entry{
+		StartedDateTime: started.UTC().Format(time.RFC3339Nano),
+		Time:            st.DurationMs,
+		Comment:         comment,
+		Timings:         Timings{Wait: st.DurationMs},
+		Request: Request{
+			Method:      st.Method,
+			URL:         st.URL,
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []NameValue{},
+			Headers:     sortedHeaders(single(st.ReqHeaders)),
+			QueryString: []NameValue{},
+			HeadersSize: -1,
+			BodySize:    len(st.ReqBody),
+		},
+		Response: Response{
+			Status:      st.StatusCode,
+			StatusText:  http.StatusText(st.StatusCode),
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []NameValue{},
+			Headers:     sortedHeaders(st.RespHeaders),
+			Content: Content{
+				Size:     len(st.RespBody),
+				MimeType: first(st.RespHeaders, "Content-Type"),
+				Text:     st.RespBody,
+			},
+			RedirectURL: first(st.RespHeaders, "Location"),
+			HeadersSize: -1,
+			BodySize:    len(st.RespBody),
+		},
+	}
+	if u, err := url.Parse(st.URL); err == nil {
+		for k, vs := range u.Query() {
+			for _, v := range vs {
+				e.Request.QueryString = append(e.Request.QueryString, NameValue{k, v})
+			}
+		}
+		sort.Slice(e.Request.QueryString, func(a, b int) bool { return e.Request.QueryString[a].Name < e.Request.QueryString[b].Name })
+	}
+	if st.ReqBody != "" {
+		mt := st.ReqHeaders["Content-Type"]
+		if mt == "" {
+			mt = "application/json"
+		}
+		e.Request.PostData = &PostData{MimeType: mt, Text: st.ReqBody}
+	}
+	return e
+}
+
+func single(m map[string]string) map[string][]string {
+	out := make(map[string][]string, len(m))
+	for k, v := range m {
+		out[k] = []string{v}
+	}
+	return out
+}
+
+func sortedHeaders(m map[string][]string) []NameValue {
+	out := []NameValue{}
+	for k, vs := range m {
+		for _, v := range vs {
+			out = append(out, NameValue{k, v})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func first(m map[string][]string, key string) string {
+	if vs := http.Header(m).Values(key); len(vs) > 0 {
+		return vs[0]
+	}
+	return ""
+}
@@ -0,0 +1,144 @@
+package har_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sea-qa/internal/executor"
+	"sea-qa/internal/har"
+	"sea-qa/internal/ir"
+	"sea-qa/internal/parser"
+)
+
+const session = `{"log":{"version":"1.2","creator":{"name":"browser","version":"1"},"entries":[
+ {"request":{"method":"GET","url":"https://app.example.com/","headers":[]},
+  "response":{"status":200,"content":{"mimeType":"text/html"}},"_resourceType":"document"},
+ {"request":{"method":"GET","url":"https://app.example.com/app.js","headers":[]},
+  "response":{"status":200,"content":{"mimeType":"application/javascript"}}},
+ {"request":{"method":"POST","url":"https://api.example.com/users","headers":[
+    {"name":"content-type","value":"application/json"},
+    {"name":"cookie","value":"session=secret"},
+    {"name":"sec-ch-ua","value":"\"Chromium\""},
+    {"name":"authorization","value":"Bearer t"}],
+   "postData":{"mimeType":"application/json","text":"{\"email\":\"a@example.com\"}"}},
+  "response":{"status":201,"content":{"mimeType":"application/json"}},"_resourceType":"fetch"},
+ {"request":{"method":"get","url":"https://api.example.com/users/7?expand=1","headers":[]},
+  "response":{"status":200,"content":{"mimeType":"application/json"}},"_resourceType":"xhr"},
+ {"request":{"method":"GET","url":"https://cdn.example.com/flags.json","headers":[]},
+  "response":{"status":404,"content":{"mimeType":"application/json"}}}
+]}}`
+
+func TestImport_FiltersAndParameterizes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.har")
+	if err := os.WriteFile(path, []byte(session), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := har.Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	data, err := parser.Marshal(har.ToSuite(f, har.ImportOptions{}))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	suite, err := parser.New().ParseBytes(data)
+	if err != nil {
+		t.Fatalf("parse:\n%s\n%v", data, err)
+	}
+
+	steps := suite.Scenarios[0].Steps
+	if len(steps) != 3 {
+		t.Fatalf("steps = %d, want 3 (static assets dropped):\n%s", len(steps), data)
+	}
+	post := steps[0].Request
+	if post.Method != "POST" || post.URL != "${BASE_URL}/users" {
+		t.Fatalf("post = %s %s", post.Method, post.URL)
+	}
+	if post.Headers["Authorization"] != "Bearer ${TOKEN}" || post.Headers["Content-Type"] != "application/json" {
+		t.Fatalf("headers = %v", post.Headers)
+	}
+	if _, ok := post.Headers["Cookie"]; ok {
+		t.Fatalf("cookie kept: %v", post.Headers)
+	}
+	if _, ok := post.Headers["Sec-Ch-Ua"]; ok {
+		t.Fatalf("browser noise kept: %v", post.Headers)
+	}
+	if body, ok := post.Body.(map[string]any); !ok || body["email"] != "a@example.com" {
+		t.Fatalf("body = %#v", post.Body)
+	}
+	if steps[1].Request.Method != "GET" || steps[1].Request.URL != "${BASE_URL}/users/7?expand=1" {
+		t.Fatalf("get = %s %s", steps[1].Request.Method, steps[1].Request.URL)
+	}
+	if steps[2].Request.URL != "https://cdn.example.com/flags.json" {
+		t.Fatalf("foreign origin rewritten: %s", steps[2].Request.URL)
+	}
+	if e := steps[2].Expect[0]; e.Type != ir.ExpectStatus || e.Value != 404 {
+		t.Fatalf("expect = %+v", e)
+	}
+
+	if all := har.ToSuite(f, har.ImportOptions{All: true}); len(all.Scenarios[0].Steps) != 5 {
+		t.Fatalf("--all kept %d steps", len(all.Scenarios[0].Steps))
+	}
+}
+
+func TestExport_FromResults(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	suite := &ir.TestSuite{Name: "s", Scenarios: []ir.Scenario{{
+		Name: "create",
+		Steps: []ir.Step{{
+			Name: "post",
+			Request: ir.Request{
+				Method:  "POST",
+				URL:     srv.URL + "/things?dry=1",
+				Headers: map[string]string{"Content-Type": "application/json"},
+				Body:    map[string]any{"a": 1},
+			},
+			Expect: []ir.Expectation{{Type: ir.ExpectStatus, Value: 201}},
+		}},
+	}}}
+	res, err := executor.New().RunSuite(context.Background(), suite)
+	if err != nil || !res.Passed {
+		t.Fatalf("run: %v %+v", err, res)
+	}
+
+	var buf bytes.Buffer
+	if err := har.Write(&buf, har.FromResults(res, "test")); err != nil {
+		t.Fatal(err)
+	}
+	var f har.File
+	if err := json.Unmarshal(buf.Bytes(), &f); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if f.Log.Version != "1.2" || len(f.Log.Entries) != 1 {
+		t.Fatalf("log = %+v", f.Log)
+	}
+	e := f.Log.Entries[0]
+	if e.Comment != "create / step 1 (post)" || e.StartedDateTime == "" {
+		t.Fatalf("entry meta = %q %q", e.Comment, e.StartedDateTime)
+	}
+	if e.Request.Method != "POST" || e.Request.PostData == nil {
+		t.Fatalf("request = %+v", e.Request)
+	}
+	var sent map[string]any
+	if err := json.Unmarshal([]byte(e.Request.PostData.Text), &sent); err != nil || sent["a"] != float64(1) {
+		t.Fatalf("postData = %q", e.Request.PostData.Text)
+	}
+	if len(e.Request.QueryString) != 1 || e.Request.QueryString[0].Name != "dry" {
+		t.Fatalf("query = %+v", e.Request.QueryString)
+	}
+	if e.Response.Status != 201 || e.Response.Content.Text != `{"ok":true}` || e.Response.Content.MimeType != "application/json" {
+		t.Fatalf("response = %+v", e.Response)
+	}
+}
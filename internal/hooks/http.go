@@ -0,0 +1,117 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"sea-qa/internal/ir"
+)
+
+// WhenHeader tells an http hook which phase it is called for.
+const WhenHeader = "X-Seaqa-Hook-When"
+
+var hookClient = &http.Client{}
+
+// RunHTTPHook POSTs in as JSON to h.URL and decodes the reply as Output.
+// Each attempt gets h.TimeoutMs; network errors, 408, 429 and 5xx are
+// retried h.Retries times with exponential backoff. An empty 2xx body is an
+// empty Output.
+func RunHTTPHook(ctx context.Context, when string, h ir.Hook, in Input) (*Output, error) {
+	if h.URL == "" {
+		return nil, fmt.Errorf("http hook: missing url")
+	}
+	payload, err := encodeInput(h, in)
+	if err != nil {
+		return nil, err
+	}
+	tmo := time.Duration(h.TimeoutMs) * time.Millisecond
+	if tmo <= 0 {
+		tmo = 10 * time.Second
+	}
+
+	var lastErr error
+	backoff := 200 * time.Millisecond
+	for attempt := 0; attempt <= h.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			if backoff *= 2; backoff > 2*time.Second {
+				backoff = 2 * time.Second
+			}
+		}
+		out, status, retry, err := postHook(ctx, when, h, payload, tmo)
+		recorderFrom(ctx).setHTTP(status, attempt+1)
+		if err == nil {
+			if when != "before" {
+				out.Request = nil
+			}
+			return out, nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	if h.Retries > 0 {
+		return nil, fmt.Errorf("http hook %s (after %d attempts): %w", h.URL, h.Retries+1, lastErr)
+	}
+	return nil, fmt.Errorf("http hook %s: %w", h.URL, lastErr)
+}
+
+// postHook makes one attempt and reports the response status (0 without a
+// response) and whether a failure is retryable.
+func postHook(ctx context.Context, when string, h ir.Hook, payload []byte, tmo time.Duration) (*Output, int, bool, error) {
+	cctx, cancel := context.WithTimeout(ctx, tmo)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(cctx, http.MethodPost, h.URL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, 0, false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(WhenHeader, when)
+	for k, v := range h.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := hookClient.Do(req)
+	if err != nil {
+		return nil, 0, ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	limit := maxOutput(h)
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(limit)+1))
+	if err != nil {
+		return nil, resp.StatusCode, true, fmt.Errorf("read reply: %w", err)
+	}
+	if len(body) > limit {
+		return nil, resp.StatusCode, false, errOutputLimit(limit)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		retry := resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		snippet := strings.TrimSpace(string(body))
+		if len(snippet) > 200 {
+			snippet = snippet[:200] + "..."
+		}
+		return nil, resp.StatusCode, retry, fmt.Errorf("status %d: %s", resp.StatusCode, snippet)
+	}
+
+	var out Output
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, &out); err != nil {
+			return nil, resp.StatusCode, false, fmt.Errorf("decode reply: %w", err)
+		}
+	}
+	return &out, resp.StatusCode, false, nil
+}
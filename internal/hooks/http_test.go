@@ -0,0 +1,104 @@
+package hooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"sea-qa/internal/hooks"
+	"sea-qa/internal/ir"
+)
+
+func TestHTTPHookRetriesAndPatches(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			http.Error(w, "warming up", http.StatusServiceUnavailable)
+			return
+		}
+		if calls.Load() == 2 && r.Header.Get(hooks.WhenHeader) != "before" {
+			t.Errorf("when header = %q", r.Header.Get(hooks.WhenHeader))
+		}
+		if got := r.Header.Get("X-Token"); got != "s3cret" {
+			t.Errorf("custom header = %q", got)
+		}
+		var in hooks.Input
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			t.Errorf("decode input: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(hooks.Output{
+			Vars:    map[string]string{"TOKEN": in.Vars["USER"] + "-tok"},
+			Request: &hooks.ReqPatch{Headers: map[string]string{"Authorization": "Bearer x"}},
+		})
+	}))
+	defer srv.Close()
+
+	m := hooks.NewManager()
+	defer m.Close()
+	h := ir.Hook{Type: "http", URL: srv.URL, Retries: 1, Headers: map[string]string{"X-Token": "s3cret"}}
+	out, err := m.Run(context.Background(), "before", h, hooks.Input{Vars: map[string]string{"USER": "ann"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, want 2", calls.Load())
+	}
+	if out.Vars["TOKEN"] != "ann-tok" || out.Request == nil {
+		t.Errorf("out = %+v", out)
+	}
+
+	// Request patches are dropped after the step.
+	out, err = m.Run(context.Background(), "after", h, hooks.Input{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Request != nil {
+		t.Errorf("after hook kept request patch: %+v", out.Request)
+	}
+}
+
+func TestHTTPHookFailures(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		switch r.URL.Path {
+		case "/bad":
+			http.Error(w, "no such hook", http.StatusBadRequest)
+		case "/slow":
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+		case "/empty":
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+	m := hooks.NewManager()
+	defer m.Close()
+	ctx := context.Background()
+
+	_, err := m.Run(ctx, "before", ir.Hook{Type: "http", URL: srv.URL + "/bad", Retries: 3}, hooks.Input{})
+	if err == nil || !strings.Contains(err.Error(), "status 400: no such hook") {
+		t.Errorf("bad: err = %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("400 was retried: %d calls", calls.Load())
+	}
+
+	_, err = m.Run(ctx, "before", ir.Hook{Type: "http", URL: srv.URL + "/slow", TimeoutMs: 50}, hooks.Input{})
+	if err == nil || !strings.Contains(err.Error(), "deadline exceeded") {
+		t.Errorf("slow: err = %v", err)
+	}
+
+	out, err := m.Run(ctx, "before", ir.Hook{Type: "http", URL: srv.URL + "/empty"}, hooks.Input{})
+	if err != nil || out == nil || out.Vars != nil || out.Request != nil {
+		t.Errorf("empty: out = %+v, err = %v", out, err)
+	}
+}
@@ -0,0 +1,380 @@
+package hooks
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"sea-qa/internal/ir"
+)
+
+// Hook modes: a fresh process per invocation, or one process per run that
+// serves newline-delimited JSON requests.
+const (
+	ModeOnce       = "once"
+	ModePersistent = "persistent"
+)
+
+const (
+	// maxStarts bounds restarts of a crashing persistent hook.
+	maxStarts = 5
+	// closeGrace is how long a persistent hook gets to exit after stdin closes.
+	closeGrace = 3 * time.Second
+)
+
+// request/reply are one line each on the persistent process's stdin/stdout.
+type request struct {
+	ID   uint64 `json:"id"`
+	When string `json:"when"`
+	Input
+}
+
+type reply struct {
+	ID     uint64 `json:"id"`
+	Stderr string `json:"stderr,omitempty"` // this request's log; the process's own stderr is shared
+	Output
+}
+
+// Manager runs hooks for one run, keeping persistent processes alive
+// between invocations. The zero value is not usable; call NewManager.
+type Manager struct {
+	mu     sync.Mutex
+	procs  map[string]*proc
+	closed bool
+}
+
+func NewManager() *Manager {
+	return &Manager{procs: map[string]*proc{}}
+}
+
+// Run executes h for the given phase ("before" or "after").
+func (m *Manager) Run(ctx context.Context, when string, h ir.Hook, in Input) (*Output, error) {
+	switch h.Type {
+	case "http":
+		return RunHTTPHook(ctx, when, h, in)
+	case "script":
+		return RunScriptHook(ctx, when, h, in)
+	}
+	switch strings.ToLower(h.Mode) {
+	case "", ModeOnce:
+		return RunProcessHook(ctx, when, h, in)
+	case ModePersistent:
+	default:
+		return nil, fmt.Errorf("unsupported hook mode %q", h.Mode)
+	}
+	if h.Type != "process" {
+		return nil, fmt.Errorf("unsupported hook type %q", h.Type)
+	}
+	p, err := m.proc(h)
+	if err != nil {
+		return nil, err
+	}
+	out, err := p.call(ctx, when, in, h.TimeoutMs)
+	if err != nil {
+		return nil, err
+	}
+	if when != "before" {
+		out.Request = nil
+	}
+	return out, nil
+}
+
+// Close shuts every persistent process down: stdin is closed so the hook
+// can exit on EOF, and it is killed if it has not exited after a grace period.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	m.closed = true
+	procs := m.procs
+	m.procs = map[string]*proc{}
+	m.mu.Unlock()
+
+	var errs []error
+	for _, p := range procs {
+		if err := p.close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Manager) proc(h ir.Hook) (*proc, error) {
+	key, _ := json.Marshal(struct {
+		Cmd        string
+		Args       []string
+		Env        map[string]string
+		Workdir    string
+		InheritEnv *bool
+		PassEnv    []string
+		MaxOutput  int
+	}{h.Cmd, h.Args, h.Env, h.Workdir, h.InheritEnv, h.PassEnv, h.MaxOutputBytes})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, errors.New("hook manager closed")
+	}
+	p := m.procs[string(key)]
+	if p == nil {
+		p = &proc{hook: h, pending: map[uint64]chan result{}}
+		m.procs[string(key)] = p
+	}
+	return p, nil
+}
+
+// ---- One persistent process ----
+
+type result struct {
+	out    *Output
+	err    error
+	stderr string // from the reply
+	exit   *int   // set when the process exited before replying...
+	tail   string // ...with the end of its stderr, already echoed
+}
+
+type proc struct {
+	hook ir.Hook
+
+	mu      sync.Mutex
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	done    chan struct{} // closed when the current process has exited
+	pending map[uint64]chan result
+	nextID  uint64
+	starts  int
+	closing bool
+
+	// tmu guards mask separately, so that echoing stderr never waits on p.mu
+	tmu  sync.Mutex
+	mask func(string) string // from the latest traced call
+}
+
+func (p *proc) name() string {
+	return strings.TrimSpace(p.hook.Cmd + " " + strings.Join(p.hook.Args, " "))
+}
+
+// start launches the process; p.mu must be held.
+func (p *proc) start() error {
+	if p.starts >= maxStarts {
+		return fmt.Errorf("persistent hook %q exited %d times; giving up", p.name(), p.starts)
+	}
+	p.starts++
+
+	cmd := exec.Command(p.hook.Cmd, p.hook.Args...)
+	prepare(cmd, p.hook, "SEAQA_HOOK_MODE="+ModePersistent)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("stdout: %w", err)
+	}
+	// masked with the run's redactor like a per-call hook's stderr
+	tee := &stderrTee{echo: prefixWriter{prefix: fmt.Sprintf("[hook %s] ", p.name()), mask: p.maskLine}}
+	cmd.Stderr = tee
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+
+	p.cmd, p.stdin, p.done = cmd, stdin, make(chan struct{})
+	go p.read(cmd, stdout, tee, p.done)
+	return nil
+}
+
+// stderrTee echoes the process's own stderr, which belongs to no request,
+// and keeps its tail for the calls that fail when the process dies.
+type stderrTee struct {
+	echo prefixWriter
+
+	mu   sync.Mutex
+	tail []byte
+}
+
+func (t *stderrTee) Write(b []byte) (int, error) {
+	t.mu.Lock()
+	t.tail = append(t.tail, b...)
+	if n := len(t.tail) - maxStderr; n > 0 {
+		t.tail = t.tail[n:]
+	}
+	t.mu.Unlock()
+	return t.echo.Write(b)
+}
+
+// read dispatches replies by id until stdout closes, then reaps the process
+// and fails whatever is still waiting on it.
+func (p *proc) read(cmd *exec.Cmd, stdout io.Reader, tee *stderrTee, done chan struct{}) {
+	limit := maxOutput(p.hook)
+	sc := bufio.NewScanner(stdout)
+	sc.Buffer(make([]byte, min(64<<10, limit)), limit)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		var rep reply
+		if err := json.Unmarshal(line, &rep); err != nil || rep.ID == 0 {
+			fmt.Fprintf(os.Stderr, "hook %q: ignoring stdout line without an id: %.200s\n", p.name(), p.maskLine(string(line)))
+			continue
+		}
+		p.mu.Lock()
+		ch := p.pending[rep.ID]
+		delete(p.pending, rep.ID)
+		p.mu.Unlock()
+		if ch != nil {
+			out := rep.Output
+			ch <- result{out: &out, stderr: rep.Stderr}
+		}
+	}
+	// stdout is no longer read, so a hook still writing would block forever
+	readErr := sc.Err()
+	if errors.Is(readErr, bufio.ErrTooLong) {
+		readErr = errOutputLimit(limit)
+	}
+	if readErr != nil {
+		_ = killProcessGroup(cmd)
+	}
+	// Wait also drains stderr, so calls failed below get all of it
+	err := cmd.Wait()
+	tee.echo.Flush()
+	tail := string(tee.tail)
+	var exit *int
+	if cmd.ProcessState != nil {
+		code := cmd.ProcessState.ExitCode()
+		exit = &code
+	}
+
+	p.mu.Lock()
+	if p.cmd == cmd {
+		p.cmd, p.stdin = nil, nil
+	}
+	exitErr := fmt.Errorf("persistent hook %q exited: %v", p.name(), err)
+	switch {
+	case readErr != nil:
+		exitErr = fmt.Errorf("persistent hook %q killed: %v", p.name(), readErr)
+	case err == nil:
+		exitErr = fmt.Errorf("persistent hook %q exited", p.name())
+	}
+	for id, ch := range p.pending {
+		ch <- result{err: exitErr, exit: exit, tail: tail}
+		delete(p.pending, id)
+	}
+	p.mu.Unlock()
+	close(done)
+}
+
+func (p *proc) call(ctx context.Context, when string, in Input, timeoutMs int) (*Output, error) {
+	tmo := time.Duration(timeoutMs) * time.Millisecond
+	if tmo <= 0 {
+		tmo = 10 * time.Second
+	}
+
+	p.mu.Lock()
+	if p.closing {
+		p.mu.Unlock()
+		return nil, errors.New("hook manager closed")
+	}
+	p.nextID++
+	id := p.nextID
+	rec := recorderFrom(ctx)
+	if rec != nil && rec.w.mask != nil {
+		p.tmu.Lock()
+		p.mask = rec.w.mask
+		p.tmu.Unlock()
+	}
+	if p.cmd == nil {
+		if err := p.start(); err != nil {
+			p.mu.Unlock()
+			return nil, err
+		}
+	}
+	ch := make(chan result, 1)
+	p.pending[id] = ch
+	stdin := p.stdin
+	p.mu.Unlock()
+
+	// The timeout covers the write too: a hook that stops reading stdin
+	// must not block this call, nor the reader and Close, which need p.mu.
+	timer := time.NewTimer(tmo)
+	defer timer.Stop()
+	line, err := encodeInput(p.hook, request{ID: id, When: when, Input: in})
+	if err != nil {
+		p.forget(id)
+		return nil, fmt.Errorf("write hook request: %w", err)
+	}
+	written := make(chan error, 1)
+	go func() {
+		// writes to the pipe are serialised, so lines never interleave
+		_, err := stdin.Write(append(line, '\n'))
+		written <- err
+	}()
+	for {
+		select {
+		case err := <-written:
+			if err != nil {
+				p.forget(id)
+				return nil, fmt.Errorf("write hook request: %w", err)
+			}
+			written = nil
+		case r := <-ch:
+			// echoed with the caller's label, unlike the process's own stderr
+			_, _ = io.WriteString(rec.stderrWriter(), r.stderr)
+			if r.exit != nil {
+				rec.setExit(*r.exit)
+				if rec != nil {
+					rec.capture([]byte(r.tail))
+				}
+			}
+			return r.out, r.err
+		case <-timer.C:
+			p.forget(id)
+			return nil, fmt.Errorf("persistent hook %q: no reply to request %d within %v", p.name(), id, tmo)
+		case <-ctx.Done():
+			p.forget(id)
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (p *proc) maskLine(line string) string {
+	p.tmu.Lock()
+	mask := p.mask
+	p.tmu.Unlock()
+	if mask == nil {
+		return line
+	}
+	return mask(line)
+}
+
+func (p *proc) forget(id uint64) {
+	p.mu.Lock()
+	delete(p.pending, id)
+	p.mu.Unlock()
+}
+
+func (p *proc) close() error {
+	p.mu.Lock()
+	p.closing = true
+	cmd, stdin, done := p.cmd, p.stdin, p.done
+	p.mu.Unlock()
+	if cmd == nil {
+		return nil
+	}
+
+	_ = stdin.Close()
+	select {
+	case <-done:
+		return nil
+	case <-time.After(closeGrace):
+	}
+	_ = killProcessGroup(cmd)
+	<-done
+	return fmt.Errorf("persistent hook %q did not exit within %v of stdin closing; killed", p.name(), closeGrace)
+}
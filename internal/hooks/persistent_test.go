@@ -0,0 +1,257 @@
+package hooks_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"sea-qa/internal/hooks"
+	"sea-qa/internal/ir"
+)
+
+// The test binary doubles as the hook: with SEAQA_TEST_HOOK=1 it serves the
+// JSON-lines protocol instead of running tests, and with =sleep it is a
+// grandchild that just lingers.
+func TestMain(m *testing.M) {
+	switch os.Getenv("SEAQA_TEST_HOOK") {
+	case "1":
+		serveHook()
+		return
+	case "sleep":
+		time.Sleep(30 * time.Second)
+		return
+	}
+	os.Exit(m.Run())
+}
+
+func serveHook() {
+	fmt.Fprint(os.Stderr, os.Getenv("SEAQA_TEST_STDERR"))
+	in := bufio.NewScanner(os.Stdin)
+	out := json.NewEncoder(os.Stdout)
+	count := 0
+	for in.Scan() {
+		var req struct {
+			ID   uint64            `json:"id"`
+			When string            `json:"when"`
+			Vars map[string]string `json:"vars"`
+		}
+		if err := json.Unmarshal(in.Bytes(), &req); err != nil {
+			os.Exit(2)
+		}
+		fmt.Fprint(os.Stderr, req.Vars["rawStderr"])
+		if req.Vars["crash"] == "1" {
+			os.Exit(3)
+		}
+		if pidFile := req.Vars["spawn"]; pidFile != "" {
+			child := exec.Command(os.Args[0], "-test.run=^$")
+			child.Env = append(os.Environ(), "SEAQA_TEST_HOOK=sleep")
+			if child.Start() != nil {
+				os.Exit(4)
+			}
+			_ = os.WriteFile(pidFile, []byte(strconv.Itoa(child.Process.Pid)), 0o644)
+		}
+		if ms, _ := strconv.Atoi(req.Vars["sleepMs"]); ms > 0 {
+			time.Sleep(time.Duration(ms) * time.Millisecond)
+		}
+		count++
+		n, _ := strconv.Atoi(req.Vars["padBytes"])
+		cwd, _ := os.Getwd()
+		_ = out.Encode(map[string]any{
+			"id": req.ID,
+			"vars": map[string]string{
+				"count":  strconv.Itoa(count),
+				"pid":    strconv.Itoa(os.Getpid()),
+				"mode":   os.Getenv("SEAQA_HOOK_MODE"),
+				"cwd":    cwd,
+				"secret": os.Getenv("SEAQA_TEST_SECRET"),
+				"path":   os.Getenv("PATH"),
+				"pad":    strings.Repeat("x", n),
+			},
+			"stderr":  req.Vars["stderr"],
+			"request": map[string]any{"headers": map[string]string{"Authorization": "Bearer cached"}},
+		})
+		if ms, _ := strconv.Atoi(req.Vars["stallMs"]); ms > 0 {
+			time.Sleep(time.Duration(ms) * time.Millisecond) // stop reading stdin
+		}
+	}
+}
+
+func hook() ir.Hook {
+	return ir.Hook{
+		Type: "process", Mode: hooks.ModePersistent, Cmd: os.Args[0],
+		Args: []string{"-test.run=^$"}, Env: map[string]string{"SEAQA_TEST_HOOK": "1"},
+		TimeoutMs: 2000,
+	}
+}
+
+func TestPersistent_ReusesProcessAndRestartsAfterCrash(t *testing.T) {
+	m := hooks.NewManager()
+	defer m.Close()
+	ctx := context.Background()
+	call := func(vars map[string]string) (*hooks.Output, error) {
+		return m.Run(ctx, "before", hook(), hooks.Input{Vars: vars, Request: &ir.Request{Method: "GET", URL: "http://x"}})
+	}
+
+	first, err := call(nil)
+	if err != nil {
+		t.Fatalf("first: %v", err)
+	}
+	if first.Vars["mode"] != hooks.ModePersistent || first.Request.Headers["Authorization"] != "Bearer cached" {
+		t.Fatalf("first = %+v", first)
+	}
+
+	// concurrent calls share the one process and get their own replies
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out, err := call(nil)
+			if err == nil && out.Vars["pid"] != first.Vars["pid"] {
+				err = os.ErrInvalid
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent call: %v", err)
+		}
+	}
+	if out, _ := call(nil); out == nil || out.Vars["count"] != "10" {
+		t.Fatalf("state not kept across calls: %+v", out)
+	}
+
+	if _, err := call(map[string]string{"crash": "1"}); err == nil || !strings.Contains(err.Error(), "exited") {
+		t.Fatalf("crash err = %v", err)
+	}
+	again, err := call(nil)
+	if err != nil {
+		t.Fatalf("after crash: %v", err)
+	}
+	if again.Vars["pid"] == first.Vars["pid"] || again.Vars["count"] != "1" {
+		t.Fatalf("not restarted: %+v", again.Vars)
+	}
+
+	h := hook()
+	h.TimeoutMs = 50
+	if _, err := m.Run(ctx, "after", h, hooks.Input{Vars: map[string]string{"sleepMs": "300"}}); err == nil || !strings.Contains(err.Error(), "no reply") {
+		t.Fatalf("timeout err = %v", err)
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, err := call(nil); err == nil {
+		t.Fatal("call after close succeeded")
+	}
+}
+
+func TestPersistent_MasksStderr(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+
+	m := hooks.NewManager()
+	h := hook()
+	h.Env["SEAQA_TEST_STDERR"] = "seeding tok-123\n"
+	tr := hooks.Trace{Label: "login / step 2", Mask: func(s string) string { return strings.ReplaceAll(s, "tok-123", "***") }}
+	in := hooks.Input{Vars: map[string]string{"stderr": "using tok-123\n"}}
+	if _, _, err := m.Exec(context.Background(), "before", h, in, tr); err != nil {
+		t.Fatal(err)
+	}
+	// Close reaps the process, so its stderr has been echoed by now.
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	os.Stderr = stderr
+	w.Close()
+	live, _ := io.ReadAll(r)
+	for _, want := range []string{"] seeding ***\n", "[login / step 2 hook(before)] using ***\n"} {
+		if !strings.Contains(string(live), want) {
+			t.Errorf("live stderr %q lacks %q", live, want)
+		}
+	}
+	if strings.Contains(string(live), "tok-123") {
+		t.Errorf("live stderr = %q", live)
+	}
+}
+
+func TestPersistent_ExecCapturesStderrAndExit(t *testing.T) {
+	m := hooks.NewManager()
+	defer m.Close()
+	h := hook()
+	h.Env["SEAQA_TEST_STDERR"] = "starting\n"
+	run := func(vars map[string]string) (hooks.Result, error) {
+		_, res, err := m.Exec(context.Background(), "before", h, hooks.Input{Vars: vars}, hooks.Trace{Label: "s"})
+		return res, err
+	}
+
+	// overlapping calls each get their own reply's stderr, and none of the
+	// process's own
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			want := fmt.Sprintf("call %d\n", i)
+			res, err := run(map[string]string{"stderr": want, "rawStderr": "noise\n", "sleepMs": "50"})
+			if err != nil || res.Stderr != want || res.ExitCode != nil {
+				t.Errorf("call %d: %v %+v", i, err, res)
+			}
+		}()
+	}
+	wg.Wait()
+
+	res, err := run(map[string]string{"rawStderr": "boom\n", "crash": "1"})
+	if err == nil || res.Error == "" {
+		t.Fatalf("want failure, got %+v", res)
+	}
+	// a dying process's stderr goes to the calls it fails
+	if res.ExitCode == nil || *res.ExitCode != 3 || !strings.HasSuffix(res.Stderr, "noise\nboom\n") {
+		t.Errorf("crash result = %+v", res)
+	}
+}
+
+func TestPersistent_TimeoutCoversBlockedWrite(t *testing.T) {
+	m := hooks.NewManager()
+	h := hook()
+	h.TimeoutMs = 200
+	ctx := context.Background()
+	if _, err := m.Run(ctx, "after", h, hooks.Input{Vars: map[string]string{"stallMs": "1500"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the request is larger than the pipe buffer and the hook is not reading
+	start := time.Now()
+	_, err := m.Run(ctx, "after", h, hooks.Input{Vars: map[string]string{"big": strings.Repeat("x", 1<<20)}})
+	if err == nil || !strings.Contains(err.Error(), "no reply") {
+		t.Fatalf("err = %v", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("call returned after %v, want about %dms", d, h.TimeoutMs)
+	}
+	done := make(chan error, 1)
+	go func() { done <- m.Close() }()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Close hung")
+	}
+}
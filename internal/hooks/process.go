@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 	"os/exec"
 	"time"
 
@@ -15,6 +14,7 @@ type Input struct {
 	Vars     map[string]string `json:"vars,omitempty"`
 	Request  *ir.Request       `json:"request,omitempty"`  // present for "before"
 	Response *Resp             `json:"response,omitempty"` // present for "after"
+	Scenario string            `json:"scenario,omitempty"` // present for scenario hooks
 }
 
 type Resp struct {
@@ -27,7 +27,30 @@ type Output struct {
 	Vars    map[string]string `json:"vars,omitempty"`    // merged into runner vars
 	Request *ReqPatch         `json:"request,omitempty"` // ONLY honored for "before"
 	Errors  []string          `json:"errors,omitempty"`  // adds step errors
-	Redact  []string          `json:"redact,omitempty"`  // reserved for future logging redaction
+	Redact  []string          `json:"redact,omitempty"`  // secret values to mask in results and reports
+
+	// Reported per step
+	Assertions  []Assertion        `json:"assertions,omitempty"`
+	Attachments []Attachment       `json:"attachments,omitempty"`
+	Metrics     map[string]float64 `json:"metrics,omitempty"`
+}
+
+// Assertion is a named check; a failed one fails the step.
+type Assertion struct {
+	Name     string `json:"name"`
+	Passed   bool   `json:"passed"`
+	Expected any    `json:"expected,omitempty"`
+	Actual   any    `json:"actual,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// Attachment carries exactly one of Text, JSON or Path (a file to include).
+type Attachment struct {
+	Name        string          `json:"name"`
+	ContentType string          `json:"contentType,omitempty"`
+	Text        string          `json:"text,omitempty"`
+	JSON        json.RawMessage `json:"json,omitempty"`
+	Path        string          `json:"path,omitempty"`
 }
 
 type ReqPatch struct {
@@ -48,14 +71,14 @@ func RunProcessHook(ctx context.Context, when string, h ir.Hook, in Input) (*Out
 	cctx, cancel := context.WithTimeout(ctx, tmo)
 	defer cancel()
 
-	cmd := exec.CommandContext(cctx, h.Cmd, h.Args...)
-
-	// inherit env + add hook env
-	cmd.Env = os.Environ()
-	for k, v := range h.Env {
-		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	payload, err := encodeInput(h, in)
+	if err != nil {
+		return nil, err
 	}
 
+	cmd := exec.CommandContext(cctx, h.Cmd, h.Args...)
+	prepare(cmd, h, "SEAQA_HOOK_WHEN="+when)
+
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
 		return nil, fmt.Errorf("stdin: %w", err)
@@ -64,22 +87,31 @@ func RunProcessHook(ctx context.Context, when string, h ir.Hook, in Input) (*Out
 	if err != nil {
 		return nil, fmt.Errorf("stdout: %w", err)
 	}
-	cmd.Stderr = os.Stderr
+	rec := recorderFrom(ctx)
+	cmd.Stderr = rec.stderrWriter()
 
 	if err := cmd.Start(); err != nil {
 		return nil, fmt.Errorf("start: %w", err)
 	}
+	defer func() {
+		if cmd.ProcessState != nil {
+			rec.setExit(cmd.ProcessState.ExitCode())
+		}
+	}()
 
-	enc := json.NewEncoder(stdin)
-	if err := enc.Encode(in); err != nil {
+	if _, err := stdin.Write(append(payload, '\n')); err != nil {
 		_ = stdin.Close()
-		return nil, fmt.Errorf("encode stdin: %w", err)
+		_ = killProcessGroup(cmd)
+		_ = cmd.Wait()
+		return nil, fmt.Errorf("write stdin: %w", err)
 	}
 	_ = stdin.Close()
 
 	var out Output
-	dec := json.NewDecoder(stdout)
+	dec := json.NewDecoder(newLimitReader(stdout, maxOutput(h)))
 	if err := dec.Decode(&out); err != nil {
+		// the hook may still be writing; don't wait for it to time out
+		_ = killProcessGroup(cmd)
 		_ = cmd.Wait()
 		return nil, fmt.Errorf("decode stdout: %w", err)
 	}
@@ -0,0 +1,111 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"sea-qa/internal/ir"
+)
+
+// defaultMaxOutput caps a hook's reply unless MaxOutputBytes is set.
+const defaultMaxOutput = 16 << 20
+
+// waitDelay bounds how long Wait waits for pipes held open by children that
+// outlive a killed hook.
+const waitDelay = 2 * time.Second
+
+// prepare applies h's workdir, environment and process-group settings to a
+// hook process; extra are added to its environment.
+func prepare(cmd *exec.Cmd, h ir.Hook, extra ...string) {
+	cmd.Dir = h.Workdir
+	cmd.Env = append(hookEnv(h), extra...)
+	for k, v := range h.Env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+	cmd.WaitDelay = waitDelay
+	setProcessGroup(cmd)
+	if cmd.Cancel != nil { // CommandContext: kill the group on timeout
+		cmd.Cancel = func() error { return killProcessGroup(cmd) }
+	}
+}
+
+// hookEnv is the runner environment a hook may see: all of it by default,
+// otherwise baseEnv and the pass_env names.
+func hookEnv(h ir.Hook) []string {
+	if h.InheritEnv == nil || *h.InheritEnv {
+		return os.Environ()
+	}
+	allow := append(append([]string(nil), baseEnv...), h.PassEnv...)
+	var env []string
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		for _, p := range allow {
+			if envMatch(p, name) {
+				env = append(env, kv)
+				break
+			}
+		}
+	}
+	return env
+}
+
+func envMatch(pattern, name string) bool {
+	if envFoldCase {
+		pattern, name = strings.ToUpper(pattern), strings.ToUpper(name)
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// encodeInput marshals v, enforcing h.MaxInputBytes.
+func encodeInput(h ir.Hook, v any) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encode hook input: %w", err)
+	}
+	if h.MaxInputBytes > 0 && len(b) > h.MaxInputBytes {
+		return nil, fmt.Errorf("hook input is %d bytes, over max_input_bytes %d", len(b), h.MaxInputBytes)
+	}
+	return b, nil
+}
+
+func maxOutput(h ir.Hook) int {
+	if h.MaxOutputBytes > 0 {
+		return h.MaxOutputBytes
+	}
+	return defaultMaxOutput
+}
+
+func errOutputLimit(limit int) error {
+	return fmt.Errorf("hook output exceeds %d bytes", limit)
+}
+
+// limitReader fails reads past limit instead of returning EOF, so a decoder
+// reports an oversized reply rather than a truncated one.
+type limitReader struct {
+	r     io.Reader
+	left  int
+	limit int
+}
+
+func newLimitReader(r io.Reader, limit int) *limitReader {
+	return &limitReader{r: r, left: limit, limit: limit}
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.left <= 0 {
+		return 0, errOutputLimit(l.limit)
+	}
+	if len(p) > l.left {
+		p = p[:l.left]
+	}
+	n, err := l.r.Read(p)
+	l.left -= n
+	return n, err
+}
@@ -0,0 +1,19 @@
+//go:build !unix && !windows
+
+package hooks
+
+import "os/exec"
+
+var baseEnv = []string{"PATH"}
+
+const envFoldCase = false
+
+// No process groups here: only the hook itself is killed.
+func setProcessGroup(*exec.Cmd) {}
+
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,134 @@
+package hooks_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"sea-qa/internal/hooks"
+)
+
+func TestProcessHookWorkdirAndEnv(t *testing.T) {
+	t.Setenv("SEAQA_TEST_SECRET", "hunter2")
+	dir := t.TempDir()
+	m := hooks.NewManager()
+	defer m.Close()
+
+	for _, mode := range []string{hooks.ModeOnce, hooks.ModePersistent} {
+		h := hook()
+		h.Mode = mode
+		h.Workdir = dir
+		out, err := m.Run(context.Background(), "before", h, hooks.Input{})
+		if err != nil {
+			t.Fatalf("%s: %v", mode, err)
+		}
+		if got, _ := filepath.EvalSymlinks(out.Vars["cwd"]); got != mustEval(t, dir) {
+			t.Errorf("%s: cwd = %q, want %q", mode, out.Vars["cwd"], dir)
+		}
+		if out.Vars["secret"] != "hunter2" {
+			t.Errorf("%s: inherited env missing secret: %v", mode, out.Vars)
+		}
+
+		inherit := false
+		h.InheritEnv = &inherit
+		out, err = m.Run(context.Background(), "before", h, hooks.Input{})
+		if err != nil {
+			t.Fatalf("%s: %v", mode, err)
+		}
+		if out.Vars["secret"] != "" || out.Vars["path"] == "" {
+			t.Errorf("%s: inherit_env false: secret %q, PATH %q", mode, out.Vars["secret"], out.Vars["path"])
+		}
+
+		h.PassEnv = []string{"SEAQA_TEST_*"}
+		out, err = m.Run(context.Background(), "before", h, hooks.Input{})
+		if err != nil {
+			t.Fatalf("%s: %v", mode, err)
+		}
+		if out.Vars["secret"] != "hunter2" {
+			t.Errorf("%s: pass_env did not pass secret: %v", mode, out.Vars)
+		}
+	}
+}
+
+func TestProcessHookSizeLimits(t *testing.T) {
+	m := hooks.NewManager()
+	defer m.Close()
+
+	for _, mode := range []string{hooks.ModeOnce, hooks.ModePersistent} {
+		h := hook()
+		h.Mode = mode
+		h.MaxInputBytes = 200
+		_, err := m.Run(context.Background(), "before", h, hooks.Input{Vars: map[string]string{"big": strings.Repeat("y", 300)}})
+		if err == nil || !strings.Contains(err.Error(), "max_input_bytes 200") {
+			t.Errorf("%s: input limit error = %v", mode, err)
+		}
+
+		h.MaxInputBytes = 0
+		h.MaxOutputBytes = 4 << 10
+		if _, err := m.Run(context.Background(), "before", h, hooks.Input{Vars: map[string]string{"padBytes": "100"}}); err != nil {
+			t.Fatalf("%s: small reply: %v", mode, err)
+		}
+		_, err = m.Run(context.Background(), "before", h, hooks.Input{Vars: map[string]string{"padBytes": "1000000"}})
+		if err == nil || !strings.Contains(err.Error(), "exceeds 4096 bytes") {
+			t.Errorf("%s: output limit error = %v", mode, err)
+		}
+	}
+}
+
+func TestProcessHookTimeoutKillsGroup(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("checks /proc")
+	}
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+	h := hook()
+	h.Mode = hooks.ModeOnce
+	h.TimeoutMs = 500
+
+	start := time.Now()
+	_, err := hooks.NewManager().Run(context.Background(), "before", h,
+		hooks.Input{Vars: map[string]string{"spawn": pidFile, "sleepMs": "10000"}})
+	if err == nil {
+		t.Fatal("expected timeout")
+	}
+	// the grandchild holds stderr open; without the group kill Wait would
+	// only return after WaitDelay
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("hook took %v to fail", d)
+	}
+	b, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, _ := strconv.Atoi(string(b))
+	deadline := time.Now().Add(2 * time.Second)
+	for alive(pid) {
+		if time.Now().After(deadline) {
+			t.Fatalf("grandchild %d still running", pid)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// alive reports whether pid exists and is not a zombie.
+func alive(pid int) bool {
+	stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return false
+	}
+	_, rest, _ := strings.Cut(string(stat), ") ")
+	return !strings.HasPrefix(rest, "Z")
+}
+
+func mustEval(t *testing.T, p string) string {
+	t.Helper()
+	p, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
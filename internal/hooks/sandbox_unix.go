@@ -0,0 +1,26 @@
+//go:build unix
+
+package hooks
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// baseEnv is always passed to hooks that don't inherit the environment.
+var baseEnv = []string{"PATH"}
+
+const envFoldCase = false
+
+// setProcessGroup starts the hook in its own process group, so a timeout
+// kills the children it spawned too.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,33 @@
+//go:build windows
+
+package hooks
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// baseEnv is always passed to hooks that don't inherit the environment;
+// many programs fail to start without SYSTEMROOT.
+var baseEnv = []string{"PATH", "SYSTEMROOT"}
+
+const envFoldCase = true
+
+// setProcessGroup starts the hook in its own process group, so a timeout
+// kills the children it spawned too.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// killProcessGroup ends the process tree with taskkill, falling back to
+// killing the hook alone.
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	if err := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run(); err != nil {
+		return cmd.Process.Kill()
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+package hooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	starjson "go.starlark.net/lib/json"
+	"go.starlark.net/lib/math"
+	startime "go.starlark.net/lib/time"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+
+	"sea-qa/internal/ir"
+)
+
+// RunScriptHook runs a Starlark script (h.Script, or the file h.File) with
+// vars, request and, after the step, response as predeclared dicts. Changes
+// to vars are returned as Output.Vars; before the step, changes to request
+// become the request patch. error(msg) adds a step error and continues;
+// fail(msg) aborts the script; check, attach and metric report structured
+// results like a hook's output. There is no file, network or load() access.
+func RunScriptHook(ctx context.Context, when string, h ir.Hook, in Input) (*Output, error) {
+	src, name := h.Script, "script"
+	if h.File != "" {
+		b, err := os.ReadFile(h.File)
+		if err != nil {
+			return nil, fmt.Errorf("read script: %w", err)
+		}
+		src, name = string(b), h.File
+	}
+	if src == "" {
+		return nil, errors.New("script hook: missing script")
+	}
+	tmo := time.Duration(h.TimeoutMs) * time.Millisecond
+	if tmo <= 0 {
+		tmo = 10 * time.Second
+	}
+
+	out := &Output{}
+	thread := &starlark.Thread{
+		Name: name,
+		Print: func(_ *starlark.Thread, msg string) {
+			if rec := recorderFrom(ctx); rec != nil {
+				fmt.Fprintln(rec, msg)
+				return
+			}
+			fmt.Fprintf(os.Stderr, "%s: %s\n", name, msg)
+		},
+	}
+	stop := context.AfterFunc(ctx, func() { thread.Cancel(ctx.Err().Error()) })
+	defer stop()
+	timer := time.AfterFunc(tmo, func() { thread.Cancel(fmt.Sprintf("timed out after %v", tmo)) })
+	defer timer.Stop()
+
+	vars := starlark.NewDict(len(in.Vars))
+	for k, v := range in.Vars {
+		_ = vars.SetKey(starlark.String(k), starlark.String(v))
+	}
+	var req, resp starlark.Value = starlark.None, starlark.None
+	if in.Request != nil {
+		r := *in.Request
+		if r.Headers == nil {
+			r.Headers = map[string]string{} // so scripts can add headers
+		}
+		v, err := toStarlark(thread, r)
+		if err != nil {
+			return nil, fmt.Errorf("script request: %w", err)
+		}
+		req = v
+	}
+	if in.Response != nil {
+		v, err := responseValue(thread, in.Response)
+		if err != nil {
+			return nil, fmt.Errorf("script response: %w", err)
+		}
+		resp = v
+	}
+
+	predeclared := starlark.StringDict{
+		"when":     starlark.String(when),
+		"vars":     vars,
+		"request":  req,
+		"response": resp,
+		"json":     starjson.Module,
+		"math":     math.Module,
+		"time":     startime.Module,
+		"error": starlark.NewBuiltin("error", func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var msg string
+			if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &msg); err != nil {
+				return nil, err
+			}
+			out.Errors = append(out.Errors, msg)
+			return starlark.None, nil
+		}),
+		"check": starlark.NewBuiltin("check", func(t *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var name, msg string
+			var passed bool
+			var expected, actual starlark.Value = starlark.None, starlark.None
+			if err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &name, "passed", &passed, "expected?", &expected, "actual?", &actual, "message?", &msg); err != nil {
+				return nil, err
+			}
+			a := Assertion{Name: name, Passed: passed, Message: msg}
+			if err := fromStarlark(t, expected, &a.Expected); err != nil {
+				return nil, fmt.Errorf("%s: expected: %v", b.Name(), err)
+			}
+			if err := fromStarlark(t, actual, &a.Actual); err != nil {
+				return nil, fmt.Errorf("%s: actual: %v", b.Name(), err)
+			}
+			out.Assertions = append(out.Assertions, a)
+			return starlark.Bool(passed), nil
+		}),
+		"attach": starlark.NewBuiltin("attach", func(t *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var name, ct string
+			var content starlark.Value
+			if err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &name, "content", &content, "content_type?", &ct); err != nil {
+				return nil, err
+			}
+			a := Attachment{Name: name, ContentType: ct}
+			if s, ok := starlark.AsString(content); ok {
+				a.Text = s
+			} else {
+				enc, err := starlark.Call(t, starjson.Module.Members["encode"], starlark.Tuple{content}, nil)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %v", b.Name(), err)
+				}
+				s, _ := starlark.AsString(enc)
+				a.JSON = json.RawMessage(s)
+			}
+			out.Attachments = append(out.Attachments, a)
+			return starlark.None, nil
+		}),
+		"metric": starlark.NewBuiltin("metric", func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var name string
+			var v starlark.Value
+			if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 2, &name, &v); err != nil {
+				return nil, err
+			}
+			f, ok := starlark.AsFloat(v)
+			if !ok {
+				return nil, fmt.Errorf("%s: value must be a number, not %s", b.Name(), v.Type())
+			}
+			if out.Metrics == nil {
+				out.Metrics = map[string]float64{}
+			}
+			out.Metrics[name] = f
+			return starlark.None, nil
+		}),
+		"sha256":        builtin1("sha256", func(s string) string { sum := sha256.Sum256([]byte(s)); return hex.EncodeToString(sum[:]) }),
+		"base64_encode": builtin1("base64_encode", func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }),
+		"base64_decode": starlark.NewBuiltin("base64_decode", func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var s string
+			if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &s); err != nil {
+				return nil, err
+			}
+			d, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", b.Name(), err)
+			}
+			return starlark.String(d), nil
+		}),
+		"hmac_sha256": starlark.NewBuiltin("hmac_sha256", func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var key, msg string
+			if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 2, &key, &msg); err != nil {
+				return nil, err
+			}
+			m := hmac.New(sha256.New, []byte(key))
+			m.Write([]byte(msg))
+			return starlark.String(hex.EncodeToString(m.Sum(nil))), nil
+		}),
+	}
+
+	opts := &syntax.FileOptions{Set: true, While: true, TopLevelControl: true, GlobalReassign: true}
+	if _, err := starlark.ExecFileOptions(opts, thread, name, src, predeclared); err != nil {
+		var ee *starlark.EvalError
+		if errors.As(err, &ee) {
+			return nil, fmt.Errorf("script: %s", ee.Backtrace())
+		}
+		return nil, fmt.Errorf("script: %w", err)
+	}
+
+	// vars: report new or changed entries
+	for _, item := range vars.Items() {
+		k, ok := starlark.AsString(item[0])
+		if !ok {
+			return nil, fmt.Errorf("script: vars key %s is not a string", item[0])
+		}
+		v, err := varString(thread, item[1])
+		if err != nil {
+			return nil, fmt.Errorf("script: vars[%q]: %w", k, err)
+		}
+		if old, ok := in.Vars[k]; !ok || old != v {
+			if out.Vars == nil {
+				out.Vars = map[string]string{}
+			}
+			out.Vars[k] = v
+		}
+	}
+
+	// request patch (honored for "before" only)
+	if when == "before" && req != starlark.None {
+		var patched ir.Request
+		if err := fromStarlark(thread, req, &patched); err != nil {
+			return nil, fmt.Errorf("script: request: %w", err)
+		}
+		out.Request = requestPatch(in.Request, &patched)
+	}
+	return out, nil
+}
+
+func builtin1(name string, f func(string) string) *starlark.Builtin {
+	return starlark.NewBuiltin(name, func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var s string
+		if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &s); err != nil {
+			return nil, err
+		}
+		return starlark.String(f(s)), nil
+	})
+}
+
+// responseValue exposes the response with headers flattened to their first
+// value and the body decoded when it is JSON (a string otherwise).
+func responseValue(thread *starlark.Thread, r *Resp) (starlark.Value, error) {
+	headers := map[string]string{}
+	for k, vs := range r.Headers {
+		if len(vs) > 0 {
+			headers[k] = vs[0]
+		}
+	}
+	var body any = string(r.Body)
+	var parsed any
+	if len(r.Body) > 0 && json.Unmarshal(r.Body, &parsed) == nil {
+		body = parsed
+	}
+	return toStarlark(thread, map[string]any{"status": r.Status, "headers": headers, "body": body})
+}
+
+// requestPatch returns the fields of after that differ from before, or nil.
+func requestPatch(before, after *ir.Request) *ReqPatch {
+	p := &ReqPatch{}
+	changed := false
+	if after.URL != before.URL {
+		p.URL, changed = after.URL, true
+	}
+	if after.Method != before.Method {
+		p.Method, changed = after.Method, true
+	}
+	for k, v := range after.Headers {
+		if before.Headers[k] != v {
+			if p.Headers == nil {
+				p.Headers = map[string]string{}
+			}
+			p.Headers[k], changed = v, true
+		}
+	}
+	a, _ := json.Marshal(after.Body)
+	b, _ := json.Marshal(before.Body)
+	if string(a) != string(b) {
+		p.Body, changed = after.Body, true
+	}
+	if !changed {
+		return nil
+	}
+	return p
+}
+
+// ---- Go <-> Starlark (via JSON) ----
+
+func toStarlark(thread *starlark.Thread, v any) (starlark.Value, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return starlark.Call(thread, starjson.Module.Members["decode"], starlark.Tuple{starlark.String(b)}, nil)
+}
+
+func fromStarlark(thread *starlark.Thread, v starlark.Value, dst any) error {
+	enc, err := starlark.Call(thread, starjson.Module.Members["encode"], starlark.Tuple{v}, nil)
+	if err != nil {
+		return err
+	}
+	s, _ := starlark.AsString(enc)
+	return json.Unmarshal([]byte(s), dst)
+}
+
+// varString stores strings as-is and anything else as JSON.
+func varString(thread *starlark.Thread, v starlark.Value) (string, error) {
+	if s, ok := starlark.AsString(v); ok {
+		return s, nil
+	}
+	enc, err := starlark.Call(thread, starjson.Module.Members["encode"], starlark.Tuple{v}, nil)
+	if err != nil {
+		return "", err
+	}
+	s, _ := starlark.AsString(enc)
+	return s, nil
+}
@@ -0,0 +1,144 @@
+package hooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"sea-qa/internal/hooks"
+	"sea-qa/internal/ir"
+)
+
+func TestScriptHookBefore(t *testing.T) {
+	src := `
+sig = hmac_sha256(vars["SECRET"], request["method"] + " " + request["url"])
+request["headers"]["X-Signature"] = sig
+request["body"]["ids"].append(3)
+vars["SIG"] = sig
+vars["COUNT"] = len(request["body"]["ids"])
+`
+	in := hooks.Input{
+		Vars: map[string]string{"SECRET": "k"},
+		Request: &ir.Request{
+			Method:  "POST",
+			URL:     "http://api/x",
+			Headers: map[string]string{"Accept": "application/json"},
+			Body:    map[string]any{"ids": []any{1, 2}},
+		},
+	}
+	out, err := hooks.NewManager().Run(context.Background(), "before", ir.Hook{Type: "script", Script: src}, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := out.Vars["SIG"]
+	if len(got) != 64 || out.Request.Headers["X-Signature"] != got {
+		t.Errorf("signature var %q, header %q", got, out.Request.Headers["X-Signature"])
+	}
+	if _, ok := out.Vars["SECRET"]; ok {
+		t.Errorf("unchanged var reported: %v", out.Vars)
+	}
+	if out.Vars["COUNT"] != "3" {
+		t.Errorf("COUNT = %q", out.Vars["COUNT"])
+	}
+	if _, ok := out.Request.Headers["Accept"]; ok || out.Request.URL != "" || out.Request.Method != "" {
+		t.Errorf("unchanged request fields in patch: %+v", out.Request)
+	}
+	b, _ := json.Marshal(out.Request.Body)
+	if string(b) != `{"ids":[1,2,3]}` {
+		t.Errorf("body = %s", b)
+	}
+}
+
+func TestScriptHookAfter(t *testing.T) {
+	src := `
+if response["status"] != 200:
+    error("status %d" % response["status"])
+items = [i for i in response["body"]["items"] if i["active"]]
+if not items:
+    error("no active item")
+else:
+    vars["ITEM_ID"] = items[0]["id"]
+if response["headers"]["Content-Type"] != "application/json":
+    error("content type")
+request["headers"]["X"] = "ignored after the step"
+`
+	in := hooks.Input{
+		Request: &ir.Request{Method: "GET", URL: "http://api/items"},
+		Response: &hooks.Resp{
+			Status:  201,
+			Headers: map[string][]string{"Content-Type": {"application/json"}},
+			Body:    json.RawMessage(`{"items":[{"id":"a","active":false},{"id":"b","active":true}]}`),
+		},
+	}
+	out, err := hooks.NewManager().Run(context.Background(), "after", ir.Hook{Type: "script", Script: src}, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Vars["ITEM_ID"] != "b" {
+		t.Errorf("ITEM_ID = %q", out.Vars["ITEM_ID"])
+	}
+	if len(out.Errors) != 1 || out.Errors[0] != "status 201" {
+		t.Errorf("errors = %v", out.Errors)
+	}
+	if out.Request != nil {
+		t.Errorf("after script patched request: %+v", out.Request)
+	}
+}
+
+func TestScriptHookErrors(t *testing.T) {
+	m := hooks.NewManager()
+	ctx := context.Background()
+	cases := map[string]struct {
+		src  string
+		want string
+	}{
+		"fail":    {`fail("bad token")`, "bad token"},
+		"syntax":  {`if x`, "script:1:5"},
+		"runtime": {"x = 1\ny = x + \"a\"", "unknown binary op"},
+		"load":    {`load("os.star", "run")`, "load not implemented"},
+		"timeout": {"while True:\n    pass", "timed out"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := m.Run(ctx, "before", ir.Hook{Type: "script", Script: tc.src, TimeoutMs: 50}, hooks.Input{})
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("err = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestScriptHookReports(t *testing.T) {
+	src := `
+body = response["body"]
+check("total matches items", body["total"] == len(body["items"]), expected=len(body["items"]), actual=body["total"])
+check("has items", len(body["items"]) > 0)
+attach("items", body["items"])
+attach("note", "first page only", content_type="text/markdown")
+metric("items", len(body["items"]))
+metric("ratio", 0.5)
+`
+	in := hooks.Input{Response: &hooks.Resp{Status: 200, Body: json.RawMessage(`{"total":3,"items":[{"id":1},{"id":2}]}`)}}
+	out, err := hooks.NewManager().Run(context.Background(), "after", ir.Hook{Type: "script", Script: src}, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Assertions) != 2 {
+		t.Fatalf("assertions = %+v", out.Assertions)
+	}
+	a := out.Assertions[0]
+	if a.Passed || a.Name != "total matches items" || a.Expected != float64(2) || a.Actual != float64(3) {
+		t.Errorf("assertion = %+v", a)
+	}
+	if !out.Assertions[1].Passed {
+		t.Errorf("has items failed")
+	}
+	if len(out.Attachments) != 2 || string(out.Attachments[0].JSON) != `[{"id":1},{"id":2}]` ||
+		out.Attachments[1].Text != "first page only" || out.Attachments[1].ContentType != "text/markdown" {
+		t.Errorf("attachments = %+v", out.Attachments)
+	}
+	if out.Metrics["items"] != 2 || out.Metrics["ratio"] != 0.5 {
+		t.Errorf("metrics = %v", out.Metrics)
+	}
+}
@@ -0,0 +1,200 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"sea-qa/internal/ir"
+)
+
+// maxStderr caps the stderr kept per invocation.
+const maxStderr = 64 << 10
+
+// Result describes one hook invocation for reports.
+type Result struct {
+	When       string  `json:"when"`
+	Type       string  `json:"type"`
+	Name       string  `json:"name"` // command line, URL or script
+	DurationMs float64 `json:"durationMs"`
+	ExitCode   *int    `json:"exitCode,omitempty"` // process hooks started per call, or a persistent process that died mid-call
+	Status     int     `json:"status,omitempty"`   // http hooks: last response status
+	Attempts   int     `json:"attempts,omitempty"` // http hooks
+	Stderr     string  `json:"stderr,omitempty"`   // process stderr or script print output
+	Output     *Output `json:"output,omitempty"`   // vars, request patch, errors; checks etc. are on the step
+	Error      string  `json:"error,omitempty"`
+}
+
+// Trace identifies an invocation. Live stderr lines are prefixed with
+// Label and passed through Mask, if set.
+type Trace struct {
+	Label string
+	Mask  func(string) string
+}
+
+// Exec runs h like Run and also reports its duration, exit code or HTTP
+// status, captured stderr and decoded output.
+func (m *Manager) Exec(ctx context.Context, when string, h ir.Hook, in Input, tr Trace) (*Output, Result, error) {
+	rec := &recorder{w: prefixWriter{prefix: fmt.Sprintf("[%s hook(%s)] ", tr.Label, when), mask: tr.Mask}}
+	start := time.Now()
+	out, err := m.Run(context.WithValue(ctx, recorderKey{}, rec), when, h, in)
+	rec.w.Flush()
+
+	res := Result{
+		When:       when,
+		Type:       h.Type,
+		Name:       hookName(h),
+		DurationMs: float64(time.Since(start).Milliseconds()),
+		ExitCode:   rec.exitCode,
+		Status:     rec.status,
+		Attempts:   rec.attempts,
+		Stderr:     rec.captured(),
+	}
+	if out != nil {
+		o := *out
+		o.Assertions, o.Attachments, o.Metrics = nil, nil, nil
+		res.Output = &o
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return out, res, err
+}
+
+func hookName(h ir.Hook) string {
+	switch h.Type {
+	case "http":
+		return h.URL
+	case "script":
+		if h.File != "" {
+			return h.File
+		}
+		return "inline script"
+	}
+	return strings.TrimSpace(h.Cmd + " " + strings.Join(h.Args, " "))
+}
+
+// ---- Per-invocation recorder (carried in the context) ----
+
+type recorderKey struct{}
+
+type recorder struct {
+	w prefixWriter
+
+	mu        sync.Mutex
+	stderr    bytes.Buffer
+	truncated bool
+	exitCode  *int
+	status    int
+	attempts  int
+}
+
+func recorderFrom(ctx context.Context) *recorder {
+	rec, _ := ctx.Value(recorderKey{}).(*recorder)
+	return rec
+}
+
+// stderrWriter is where a hook's stderr goes: captured and echoed with a
+// prefix when traced, straight to os.Stderr otherwise.
+func (rec *recorder) stderrWriter() io.Writer {
+	if rec == nil {
+		return os.Stderr
+	}
+	return rec
+}
+
+func (rec *recorder) Write(p []byte) (int, error) {
+	rec.capture(p)
+	return rec.w.Write(p)
+}
+
+// capture keeps p without echoing it.
+func (rec *recorder) capture(p []byte) {
+	rec.mu.Lock()
+	room := maxStderr - rec.stderr.Len()
+	if len(p) > room {
+		rec.truncated = true
+	}
+	if room > 0 {
+		rec.stderr.Write(p[:min(len(p), room)])
+	}
+	rec.mu.Unlock()
+}
+
+func (rec *recorder) captured() string {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.truncated {
+		return rec.stderr.String() + "\n(truncated)"
+	}
+	return rec.stderr.String()
+}
+
+func (rec *recorder) setExit(code int) {
+	if rec != nil {
+		rec.mu.Lock()
+		rec.exitCode = &code
+		rec.mu.Unlock()
+	}
+}
+
+func (rec *recorder) setHTTP(status, attempts int) {
+	if rec != nil {
+		rec.mu.Lock()
+		rec.status, rec.attempts = status, attempts
+		rec.mu.Unlock()
+	}
+}
+
+// ---- Prefixed live stderr ----
+
+// stderrMu keeps lines from concurrent hooks whole.
+var stderrMu sync.Mutex
+
+// prefixWriter echoes complete lines to os.Stderr, each with prefix.
+type prefixWriter struct {
+	prefix string
+	mask   func(string) string
+
+	mu      sync.Mutex
+	partial []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(string(w.partial[:i]))
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes a trailing line without a newline.
+func (w *prefixWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.partial) > 0 {
+		w.emit(string(w.partial))
+		w.partial = nil
+	}
+}
+
+func (w *prefixWriter) emit(line string) {
+	if w.mask != nil {
+		line = w.mask(line)
+	}
+	stderrMu.Lock()
+	fmt.Fprint(os.Stderr, w.prefix+strings.TrimSuffix(line, "\r")+"\n")
+	stderrMu.Unlock()
+}
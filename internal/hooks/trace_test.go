@@ -0,0 +1,75 @@
+package hooks_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sea-qa/internal/hooks"
+	"sea-qa/internal/ir"
+)
+
+func TestExecCapturesProcessHook(t *testing.T) {
+	m := hooks.NewManager()
+	defer m.Close()
+	h := hook()
+	h.Mode = ""
+	h.Env = map[string]string{"SEAQA_TEST_HOOK": "1", "SEAQA_TEST_STDERR": "seeding tok-123\npartial"}
+
+	tr := hooks.Trace{Label: "Users / step 1", Mask: func(s string) string { return strings.ReplaceAll(s, "tok-123", "***") }}
+	out, res, err := m.Exec(context.Background(), "before", h, hooks.Input{}, tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Vars["count"] != "1" {
+		t.Errorf("out = %+v", out)
+	}
+	if res.When != "before" || res.ExitCode == nil || *res.ExitCode != 0 {
+		t.Errorf("result = %+v", res)
+	}
+	// Captured stderr is raw; masking applies to the live echo and to reports.
+	if res.Stderr != "seeding tok-123\npartial" {
+		t.Errorf("stderr = %q", res.Stderr)
+	}
+	if res.Output == nil || res.Output.Vars["count"] != "1" || !strings.Contains(res.Name, "-test.run") {
+		t.Errorf("result = %+v", res)
+	}
+
+	h.Env["SEAQA_TEST_STDERR"] = "boom\n"
+	_, res, err = m.Exec(context.Background(), "before", h, hooks.Input{Vars: map[string]string{"crash": "1"}}, tr)
+	if err == nil || res.Error == "" {
+		t.Fatalf("want failure, got %+v", res)
+	}
+	if res.ExitCode == nil || *res.ExitCode != 3 || res.Stderr != "boom\n" || res.Output != nil {
+		t.Errorf("result = %+v", res)
+	}
+}
+
+func TestExecRecordsHTTPStatus(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls++; calls == 1 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"vars":{"a":"1"},"metrics":{"ms":2}}`))
+	}))
+	defer srv.Close()
+
+	m := hooks.NewManager()
+	defer m.Close()
+	h := ir.Hook{Type: "http", URL: srv.URL, Retries: 2}
+	out, res, err := m.Exec(context.Background(), "after", h, hooks.Input{}, hooks.Trace{Label: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != 200 || res.Attempts != 2 || res.ExitCode != nil || res.Name != srv.URL {
+		t.Errorf("result = %+v", res)
+	}
+	// Step-level reports stay on the Output, not the per-invocation copy.
+	if out.Metrics["ms"] != 2 || res.Output.Metrics != nil || res.Output.Vars["a"] != "1" {
+		t.Errorf("out = %+v, result output = %+v", out, res.Output)
+	}
+}
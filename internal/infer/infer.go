@@ -0,0 +1,673 @@
+package infer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/invopop/yaml"
+
+	"sea-qa/internal/executor"
+	"sea-qa/internal/har"
+)
+
+// Observation is one request/response pair seen on the wire.
+type Observation struct {
+	Method          string
+	URL             string
+	ReqContentType  string
+	ReqBody         string
+	Status          int
+	RespContentType string
+	RespBody        string
+}
+
+// FromResults collects the executed steps of a run (results.json).
+func FromResults(res *executor.SuiteResult) []Observation {
+	var out []Observation
+	for _, sc := range res.Scenarios {
+		for _, st := range sc.Steps {
+			if st.Method == "" || st.URL == "" || st.StatusCode == 0 {
+				continue
+			}
+			out = append(out, Observation{
+				Method:          st.Method,
+				URL:             st.URL,
+				ReqContentType:  headerOf(st.ReqHeaders, "Content-Type"),
+				ReqBody:         st.ReqBody,
+				Status:          st.StatusCode,
+				RespContentType: http.Header(st.RespHeaders).Get("Content-Type"),
+				RespBody:        st.RespBody,
+			})
+		}
+	}
+	return out
+}
+
+// FromHAR collects the entries of a HAR file that got a response.
+func FromHAR(f *har.File) []Observation {
+	var out []Observation
+	for _, e := range f.Log.Entries {
+		if e.Response.Status == 0 {
+			continue
+		}
+		o := Observation{
+			Method:          e.Request.Method,
+			URL:             e.Request.URL,
+			Status:          e.Response.Status,
+			RespContentType: e.Response.Content.MimeType,
+			RespBody:        e.Response.Content.Text,
+		}
+		if e.Response.Content.Encoding == "base64" {
+			if b, err := base64.StdEncoding.DecodeString(o.RespBody); err == nil {
+				o.RespBody = string(b)
+			}
+		}
+		if pd := e.Request.PostData; pd != nil {
+			o.ReqContentType, o.ReqBody = pd.MimeType, pd.Text
+		}
+		out = append(out, o)
+	}
+	return out
+}
+
+type Options struct {
+	Title   string // info.title; defaults to "Inferred API"
+	Version string // info.version; defaults to "0.0.0"
+}
+
+// ---- Inference ----
+
+type operation struct {
+	method   string
+	template string
+	params   []pathParam
+	query    map[string][]string // name -> observed values
+	queryN   map[string]int      // name -> observations carrying it
+	n        int
+	reqN     int
+	reqBody  map[string]*shape // media type -> shape
+	statuses map[int]map[string]*shape
+}
+
+type pathParam struct {
+	name   string
+	values []string
+}
+
+// Infer builds an OpenAPI 3.0 document: paths are templated from the
+// observed URLs, schemas inferred from JSON bodies, and every status seen is
+// listed per operation. No servers are emitted so the document can validate
+// traffic against any base URL; the observed origins go into the description.
+func Infer(obs []Observation, opt Options) *openapi3.T {
+	title := opt.Title
+	if title == "" {
+		title = "Inferred API"
+	}
+	version := opt.Version
+	if version == "" {
+		version = "0.0.0"
+	}
+
+	type parsed struct {
+		o    Observation
+		segs []string
+		q    url.Values
+	}
+	var all []parsed
+	origins := map[string]int{}
+	for _, o := range obs {
+		u, err := url.Parse(o.URL)
+		if err != nil {
+			continue
+		}
+		if u.Host != "" {
+			origins[u.Scheme+"://"+u.Host]++
+		}
+		all = append(all, parsed{o: o, segs: segments(u.Path), q: u.Query()})
+	}
+
+	// Templates are shared across methods so GET and DELETE /users/{id} agree.
+	paths := make([][]string, len(all))
+	for i, p := range all {
+		paths[i] = p.segs
+	}
+	tmpls := templates(paths)
+
+	ops := map[string]*operation{}
+	var keys []string
+	for i, p := range all {
+		t := tmpls[i]
+		method := strings.ToUpper(p.o.Method)
+		key := t.path + " " + method
+		op := ops[key]
+		if op == nil {
+			op = &operation{
+				method: method, template: t.path,
+				query: map[string][]string{}, queryN: map[string]int{},
+				reqBody: map[string]*shape{}, statuses: map[int]map[string]*shape{},
+			}
+			for _, name := range t.names {
+				op.params = append(op.params, pathParam{name: name})
+			}
+			ops[key] = op
+			keys = append(keys, key)
+		}
+		op.n++
+		for j, pos := range t.positions {
+			op.params[j].values = append(op.params[j].values, p.segs[pos])
+		}
+		for name, vs := range p.q {
+			op.queryN[name]++
+			op.query[name] = append(op.query[name], vs...)
+		}
+		if p.o.ReqBody != "" {
+			op.reqN++
+			mt := mediaType(p.o.ReqContentType, p.o.ReqBody)
+			if op.reqBody[mt] == nil {
+				op.reqBody[mt] = &shape{}
+			}
+			op.reqBody[mt].addBody(mt, p.o.ReqBody)
+		}
+		byType := op.statuses[p.o.Status]
+		if byType == nil {
+			byType = map[string]*shape{}
+			op.statuses[p.o.Status] = byType
+		}
+		if p.o.RespBody != "" {
+			mt := mediaType(p.o.RespContentType, p.o.RespBody)
+			if byType[mt] == nil {
+				byType[mt] = &shape{}
+			}
+			byType[mt].addBody(mt, p.o.RespBody)
+		}
+	}
+	sort.Strings(keys)
+
+	doc := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info:    &openapi3.Info{Title: title, Version: version, Description: describe(len(all), origins)},
+		Paths:   openapi3.NewPaths(),
+	}
+	for _, key := range keys {
+		op := ops[key]
+		item := doc.Paths.Value(op.template)
+		if item == nil {
+			item = &openapi3.PathItem{}
+			doc.Paths.Set(op.template, item)
+		}
+		item.SetOperation(op.method, op.build())
+	}
+	return doc
+}
+
+func (op *operation) build() *openapi3.Operation {
+	o := openapi3.NewOperation()
+	o.Summary = fmt.Sprintf("Observed %d time(s)", op.n)
+	if tag := firstLiteral(op.template); tag != "" {
+		o.Tags = []string{tag}
+	}
+
+	for _, p := range op.params {
+		param := openapi3.NewPathParameter(p.name).WithSchema(valueSchema(p.values))
+		o.AddParameter(param)
+	}
+	names := make([]string, 0, len(op.query))
+	for name := range op.query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		param := openapi3.NewQueryParameter(name).WithSchema(valueSchema(op.query[name]))
+		param.Required = op.queryN[name] == op.n
+		o.AddParameter(param)
+	}
+
+	if op.reqN > 0 {
+		body := openapi3.NewRequestBody().WithRequired(op.reqN == op.n)
+		body.Content = content(op.reqBody)
+		o.RequestBody = &openapi3.RequestBodyRef{Value: body}
+	}
+
+	o.Responses = openapi3.NewResponses()
+	o.Responses.Delete("default")
+	codes := make([]int, 0, len(op.statuses))
+	for code := range op.statuses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		desc := http.StatusText(code)
+		if desc == "" {
+			desc = "Observed"
+		}
+		r := openapi3.NewResponse().WithDescription(desc)
+		if len(op.statuses[code]) > 0 {
+			r.Content = content(op.statuses[code])
+		}
+		o.Responses.Set(strconv.Itoa(code), &openapi3.ResponseRef{Value: r})
+	}
+	return o
+}
+
+func content(byType map[string]*shape) openapi3.Content {
+	c := openapi3.Content{}
+	for mt, s := range byType {
+		c[mt] = openapi3.NewMediaType().WithSchemaRef(s.schema())
+	}
+	return c
+}
+
+func describe(n int, origins map[string]int) string {
+	list := make([]string, 0, len(origins))
+	for o := range origins {
+		list = append(list, o)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if origins[list[i]] != origins[list[j]] {
+			return origins[list[i]] > origins[list[j]]
+		}
+		return list[i] < list[j]
+	})
+	d := fmt.Sprintf("Inferred by sea-qa from %d observed request(s).", n)
+	if len(list) > 0 {
+		d += " Origins: " + strings.Join(list, ", ") + "."
+	}
+	return d + " Review names, required fields and formats before enforcing."
+}
+
+// Marshal renders the document as YAML.
+func Marshal(doc *openapi3.T) ([]byte, error) {
+	j, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("encode openapi: %w", err)
+	}
+	y, err := yaml.JSONToYAML(j)
+	if err != nil {
+		return nil, fmt.Errorf("encode openapi: %w", err)
+	}
+	return y, nil
+}
+
+// ---- Path templating ----
+
+type template struct {
+	path      string
+	names     []string
+	positions []int // segment index of each parameter
+}
+
+var (
+	uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hexRe  = regexp.MustCompile(`^[0-9a-fA-F]{12,}$`)
+	digits = regexp.MustCompile(`[0-9]`)
+	tokRe  = regexp.MustCompile(`^[A-Za-z0-9_-]{16,}$`)
+)
+
+// minVariants is how many distinct literal values at one position (with
+// everything else equal) turn that position into a parameter.
+const minVariants = 3
+
+func segments(p string) []string {
+	var out []string
+	for _, s := range strings.Split(p, "/") {
+		if s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// looksLikeID reports segments that are clearly values: numbers, UUIDs,
+// long hex strings and long opaque tokens containing digits.
+func looksLikeID(s string) bool {
+	if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return true
+	}
+	if uuidRe.MatchString(s) {
+		return true
+	}
+	return digits.MatchString(s) && (hexRe.MatchString(s) || tokRe.MatchString(s))
+}
+
+// templates maps every path to its template. Segments are variable when
+// they look like IDs, or when at least minVariants different literals occur
+// at the same position of otherwise identical paths whose parent was itself
+// requested (/users plus /users/ann, /users/bob, ... but not /api/users,
+// /api/orders, ... unless /api was called too).
+func templates(paths [][]string) []template {
+	const hole = "\x00"
+	shapes := make([][]string, len(paths))
+	for i, segs := range paths {
+		s := make([]string, len(segs))
+		for j, seg := range segs {
+			if looksLikeID(seg) {
+				s[j] = hole
+			} else {
+				s[j] = seg
+			}
+		}
+		shapes[i] = s
+	}
+
+	// Widen positions with many literal variants until nothing changes.
+	for changed := true; changed; {
+		changed = false
+		variants := map[string]map[string]bool{} // shape with position blanked -> literals
+		seen := map[string]bool{}
+		for _, s := range shapes {
+			seen[strings.Join(s, "/")] = true
+		}
+		for _, s := range shapes {
+			for j, seg := range s {
+				if seg == hole || j == 0 || !seen[strings.Join(s[:j], "/")] {
+					continue
+				}
+				k := blank(s, j)
+				if variants[k] == nil {
+					variants[k] = map[string]bool{}
+				}
+				variants[k][seg] = true
+			}
+		}
+		for _, s := range shapes {
+			for j, seg := range s {
+				if seg != hole && len(variants[blank(s, j)]) >= minVariants {
+					s[j] = hole
+					changed = true
+				}
+			}
+		}
+	}
+
+	out := make([]template, len(paths))
+	for i, s := range shapes {
+		var (
+			t    template
+			segs = make([]string, len(s))
+			used = map[string]int{}
+		)
+		for j, seg := range s {
+			if seg != hole {
+				segs[j] = seg
+				continue
+			}
+			name := "id"
+			if j > 0 && s[j-1] != hole {
+				name = singular(s[j-1]) + "Id"
+			}
+			name = camel(name)
+			used[name]++
+			if used[name] > 1 {
+				name += strconv.Itoa(used[name])
+			}
+			segs[j] = "{" + name + "}"
+			t.names = append(t.names, name)
+			t.positions = append(t.positions, j)
+		}
+		t.path = "/" + strings.Join(segs, "/")
+		out[i] = t
+	}
+	return out
+}
+
+func blank(s []string, j int) string {
+	var b strings.Builder
+	b.WriteString(strconv.Itoa(len(s)))
+	for i, seg := range s {
+		b.WriteByte('/')
+		if i == j {
+			b.WriteString("*")
+		} else {
+			b.WriteString(seg)
+		}
+	}
+	return b.String()
+}
+
+func singular(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies") && len(s) > 3:
+		return s[:len(s)-3] + "y"
+	case strings.HasSuffix(s, "sses"), strings.HasSuffix(s, "xes"), strings.HasSuffix(s, "ches"), strings.HasSuffix(s, "shes"):
+		return s[:len(s)-2]
+	case strings.HasSuffix(s, "s") && !strings.HasSuffix(s, "ss") && len(s) > 1:
+		return s[:len(s)-1]
+	}
+	return s
+}
+
+// camel turns "order-items" / "order_items" into "orderItems".
+func camel(s string) string {
+	parts := strings.FieldsFunc(s, func(r rune) bool { return r == '-' || r == '_' || r == '.' })
+	for i := 1; i < len(parts); i++ {
+		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+	}
+	return strings.Join(parts, "")
+}
+
+func firstLiteral(template string) string {
+	for _, s := range segments(template) {
+		if !strings.HasPrefix(s, "{") {
+			return s
+		}
+	}
+	return ""
+}
+
+// ---- Schemas ----
+
+func mediaType(ct, body string) string {
+	if ct != "" {
+		if mt, _, err := mime.ParseMediaType(ct); err == nil {
+			return mt
+		}
+	}
+	if json.Valid([]byte(body)) {
+		return "application/json"
+	}
+	return "text/plain"
+}
+
+func isJSON(mt string) bool {
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+// shape accumulates every value seen at one place in a document.
+type shape struct {
+	n       int
+	types   map[string]int
+	props   map[string]*shape
+	objects int
+	items   *shape
+	strs    []string
+}
+
+func (s *shape) addBody(mt, body string) {
+	if !isJSON(mt) {
+		s.add(body)
+		return
+	}
+	dec := json.NewDecoder(strings.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		s.add(body)
+		return
+	}
+	s.add(v)
+}
+
+func (s *shape) add(v any) {
+	s.n++
+	if s.types == nil {
+		s.types = map[string]int{}
+	}
+	switch x := v.(type) {
+	case nil:
+		s.types["null"]++
+	case bool:
+		s.types["boolean"]++
+	case json.Number:
+		if _, err := x.Int64(); err == nil {
+			s.types["integer"]++
+		} else {
+			s.types["number"]++
+		}
+	case string:
+		s.types["string"]++
+		s.strs = append(s.strs, x)
+	case []any:
+		s.types["array"]++
+		if s.items == nil {
+			s.items = &shape{}
+		}
+		for _, e := range x {
+			s.items.add(e)
+		}
+	case map[string]any:
+		s.types["object"]++
+		s.objects++
+		if s.props == nil {
+			s.props = map[string]*shape{}
+		}
+		for k, e := range x {
+			if s.props[k] == nil {
+				s.props[k] = &shape{}
+			}
+			s.props[k].add(e)
+		}
+	}
+}
+
+func (s *shape) schema() *openapi3.SchemaRef {
+	nullable := s.types["null"] > 0
+	var kinds []string
+	for t := range s.types {
+		if t != "null" {
+			kinds = append(kinds, t)
+		}
+	}
+	if len(kinds) == 2 && s.types["integer"] > 0 && s.types["number"] > 0 {
+		kinds = []string{"number"}
+	}
+
+	var sc *openapi3.Schema
+	if len(kinds) != 1 {
+		// mixed or only null: leave the type open
+		sc = openapi3.NewSchema()
+	} else {
+		switch kinds[0] {
+		case "object":
+			sc = openapi3.NewObjectSchema()
+			keys := make([]string, 0, len(s.props))
+			for k := range s.props {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				p := s.props[k]
+				sc.Properties[k] = p.schema()
+				if p.n == s.objects && p.types["null"] == 0 {
+					sc.Required = append(sc.Required, k)
+				}
+			}
+		case "array":
+			sc = openapi3.NewArraySchema()
+			if s.items != nil && s.items.n > 0 {
+				sc.Items = s.items.schema()
+			} else {
+				sc.Items = openapi3.NewSchemaRef("", openapi3.NewSchema())
+			}
+		case "string":
+			sc = openapi3.NewStringSchema()
+			sc.Format = stringFormat(s.strs)
+		case "integer":
+			sc = openapi3.NewIntegerSchema()
+		case "number":
+			sc = openapi3.NewFloat64Schema()
+		case "boolean":
+			sc = openapi3.NewBoolSchema()
+		}
+	}
+	sc.Nullable = nullable
+	return openapi3.NewSchemaRef("", sc)
+}
+
+var (
+	dateTimeRe = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})$`)
+	dateRe     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	emailRe    = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+)
+
+// stringFormat returns a format every observed value satisfies.
+func stringFormat(vs []string) string {
+	if len(vs) == 0 {
+		return ""
+	}
+	formats := []struct {
+		name string
+		ok   func(string) bool
+	}{
+		{"date-time", dateTimeRe.MatchString},
+		{"date", dateRe.MatchString},
+		{"uuid", uuidRe.MatchString},
+		{"email", emailRe.MatchString},
+	}
+	for _, f := range formats {
+		all := true
+		for _, v := range vs {
+			if !f.ok(v) {
+				all = false
+				break
+			}
+		}
+		if all {
+			return f.name
+		}
+	}
+	return ""
+}
+
+// valueSchema types path/query values: integers, booleans, UUIDs or strings.
+func valueSchema(vs []string) *openapi3.Schema {
+	ints, bools := true, true
+	for _, v := range vs {
+		if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+			ints = false
+		}
+		if v != "true" && v != "false" {
+			bools = false
+		}
+	}
+	switch {
+	case len(vs) == 0:
+		return openapi3.NewStringSchema()
+	case ints:
+		return openapi3.NewIntegerSchema()
+	case bools:
+		return openapi3.NewBoolSchema()
+	}
+	s := openapi3.NewStringSchema()
+	if f := stringFormat(vs); f == "uuid" || f == "date" || f == "date-time" {
+		s.Format = f
+	}
+	return s
+}
+
+func headerOf(h map[string]string, key string) string {
+	for k, v := range h {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,117 @@
+package infer_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sea-qa/internal/contract"
+	"sea-qa/internal/executor"
+	"sea-qa/internal/infer"
+	"sea-qa/internal/ir"
+)
+
+func backend() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		p := r.URL.Path
+		switch {
+		case p == "/users" && r.Method == "POST":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":3,"email":"c@example.com","createdAt":"2024-05-01T10:00:00Z"}`))
+		case p == "/users":
+			_, _ = w.Write([]byte(`{"total":2,"items":[{"id":1,"email":"a@example.com","nick":null},{"id":2,"email":"b@example.com"}]}`))
+		case p == "/users/404":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"no such user"}`))
+		case strings.HasPrefix(p, "/users/"):
+			_, _ = w.Write([]byte(`{"id":1,"email":"a@example.com","score":1.5}`))
+		case strings.HasPrefix(p, "/teams/"):
+			_, _ = w.Write([]byte(`{"slug":"` + strings.TrimPrefix(p, "/teams/") + `"}`))
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+}
+
+func TestInfer_SpecLoadsAndValidatesObservedTraffic(t *testing.T) {
+	srv := backend()
+	defer srv.Close()
+
+	get := func(path string) ir.Step {
+		return ir.Step{Request: ir.Request{Method: "GET", URL: "${BASE_URL}" + path}}
+	}
+	suite := &ir.TestSuite{Name: "traffic", Scenarios: []ir.Scenario{{Name: "s", Steps: []ir.Step{
+		get("/users?page=1"), get("/users"),
+		{Request: ir.Request{Method: "POST", URL: "${BASE_URL}/users", Headers: map[string]string{"Content-Type": "application/json"}, Body: map[string]any{"email": "c@example.com"}}},
+		get("/users/1"), get("/users/2"), get("/users/404"),
+		get("/teams"), get("/teams/red"), get("/teams/blue"), get("/teams/green"),
+		get("/api/alpha"), get("/api/beta"), get("/api/gamma"),
+	}}}}
+	r := executor.NewWithVars(map[string]string{"BASE_URL": srv.URL})
+	res, err := r.RunSuite(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	obs := infer.FromResults(res)
+	doc := infer.Infer(obs, infer.Options{Title: "Users"})
+	data, err := infer.Marshal(doc)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	v, err := contract.LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("load inferred spec:\n%s\n%v", data, err)
+	}
+
+	var got []string
+	for _, p := range v.Doc().Paths.InMatchingOrder() {
+		got = append(got, p)
+	}
+	want := []string{"/api/alpha", "/api/beta", "/api/gamma", "/teams", "/teams/{teamId}", "/users", "/users/{userId}"}
+	for _, w := range want {
+		if v.Doc().Paths.Value(w) == nil {
+			t.Fatalf("missing path %s; got %v", w, got)
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("paths = %v, want %v", got, want)
+	}
+
+	list := v.Doc().Paths.Value("/users").Get
+	if q := list.Parameters.GetByInAndName("query", "page"); q == nil || q.Required {
+		t.Fatalf("page param = %+v", q)
+	}
+	byID := v.Doc().Paths.Value("/users/{userId}").Get
+	if byID.Responses.Status(200) == nil || byID.Responses.Status(404) == nil {
+		t.Fatalf("statuses: %v", byID.Responses.Map())
+	}
+	if typ := byID.Parameters.GetByInAndName("path", "userId").Schema.Value.Type; !typ.Is("integer") {
+		t.Fatalf("userId type = %v", typ)
+	}
+	created := v.Doc().Paths.Value("/users").Post.Responses.Status(201).Value.Content.Get("application/json").Schema.Value
+	if f := created.Properties["createdAt"].Value.Format; f != "date-time" {
+		t.Fatalf("createdAt format = %q", f)
+	}
+	item := v.Doc().Paths.Value("/users").Get.Responses.Status(200).Value.Content.Get("application/json").Schema.Value.Properties["items"].Value.Items.Value
+	if !item.Properties["nick"].Value.Nullable || strings.Join(item.Required, ",") != "email,id" {
+		t.Fatalf("items schema: required=%v nick=%+v", item.Required, item.Properties["nick"].Value)
+	}
+
+	// every observed exchange conforms to the inferred contract
+	for _, o := range obs {
+		if _, _, err := v.ValidateResponse(context.Background(), o.Method, o.URL, o.Status,
+			map[string][]string{"Content-Type": {o.RespContentType}}, []byte(o.RespBody)); err != nil {
+			t.Fatalf("%s %s: %v", o.Method, o.URL, err)
+		}
+	}
+}
@@ -5,12 +5,39 @@ const (
 	ExpectStatus   = "status"
 	ExpectJSONPath = "jsonPath"
 	ExpectContract = "contract"
+	ExpectExpr     = "expr"
 )
 
 type TestSuite struct {
-	Name      string     `json:"name" yaml:"name"`
-	OpenAPI   string     `json:"openapi,omitempty" yaml:"openapi,omitempty"`
-	Scenarios []Scenario `json:"scenarios" yaml:"scenarios"`
+	Name      string                 `json:"name" yaml:"name"`
+	OpenAPI   string                 `json:"openapi,omitempty" yaml:"openapi,omitempty"`
+	OpenAPIs  map[string]OpenAPISpec `json:"openapis,omitempty" yaml:"openapis,omitempty"`
+	Scenarios []Scenario             `json:"scenarios" yaml:"scenarios"`
+	Hooks     []Hook                 `json:"hooks,omitempty" yaml:"hooks,omitempty"` // beforeSuite/afterSuite; beforeScenario/afterScenario run for every scenario
+	Redact    *Redaction             `json:"redact,omitempty" yaml:"redact,omitempty"`
+}
+
+// Redaction lists what to mask in results and reports, on top of the
+// built-in credential headers and secret-looking var names. Names may use
+// * wildcards and match case-insensitively.
+type Redaction struct {
+	Vars      []string `json:"vars,omitempty" yaml:"vars,omitempty"`           // var names whose values are secret
+	Headers   []string `json:"headers,omitempty" yaml:"headers,omitempty"`     // request/response header names
+	JSONPaths []string `json:"jsonPaths,omitempty" yaml:"jsonPaths,omitempty"` // $.a.b, $.items[*].token, $..password
+}
+
+// DefaultSpec names the openapi/--openapi spec when openapis is also set;
+// it is reserved as an openapis key.
+const DefaultSpec = "default"
+
+// OpenAPISpec routes requests to one of several specs (one per service).
+// At least one of Host, BaseURL or Prefix must be set; the most specific match wins.
+type OpenAPISpec struct {
+	File        string `json:"file" yaml:"file"`
+	Host        string `json:"host,omitempty" yaml:"host,omitempty"`
+	BaseURL     string `json:"base_url,omitempty" yaml:"base_url,omitempty"` // may use ${VARS}
+	Prefix      string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	StripPrefix bool   `json:"strip_prefix,omitempty" yaml:"strip_prefix,omitempty"`
 }
 
 type Scenario struct {
@@ -20,6 +47,7 @@ type Scenario struct {
 	Setup    []Action `json:"setup,omitempty" yaml:"setup,omitempty"`
 	Steps    []Step   `json:"steps" yaml:"steps"`
 	Teardown []Action `json:"teardown,omitempty" yaml:"teardown,omitempty"`
+	Hooks    []Hook   `json:"hooks,omitempty" yaml:"hooks,omitempty"` // beforeScenario/afterScenario
 }
 
 type Action struct {
@@ -32,13 +60,20 @@ type Step struct {
 	Request Request       `json:"request" yaml:"request"`
 	Expect  []Expectation `json:"expect,omitempty" yaml:"expect,omitempty"`
 	Hooks   []Hook        `json:"hooks,omitempty" yaml:"hooks,omitempty"`
+	Script  *StepScript   `json:"script,omitempty" yaml:"script,omitempty"`
+}
+
+// StepScript is shorthand for script hooks run before and after the request.
+type StepScript struct {
+	Before string `json:"before,omitempty" yaml:"before,omitempty"`
+	After  string `json:"after,omitempty" yaml:"after,omitempty"`
 }
 
 type Request struct {
 	Method    string            `yaml:"method"  json:"method"`
 	URL       string            `yaml:"url"     json:"url"`
-	Headers   map[string]string `yaml:"headers" json:"headers"`
-	Body      any               `yaml:"body"    json:"body"`
+	Headers   map[string]string `yaml:"headers,omitempty" json:"headers"`
+	Body      any               `yaml:"body,omitempty"    json:"body"`
 	TimeoutMs int               `yaml:"timeout_ms,omitempty" json:"timeout_ms,omitempty"`
 }
 
@@ -49,11 +84,28 @@ type Expectation struct {
 }
 
 type Hook struct {
-	Type      string            `json:"type" yaml:"type"` // "process"
-	When      string            `json:"when" yaml:"when"` // "before" | "after"
-	Cmd       string            `json:"cmd" yaml:"cmd"`
+	Type      string            `json:"type" yaml:"type"`                     // "process" | "http" | "script"
+	When      string            `json:"when" yaml:"when"`                     // steps: "before" | "after"; scenarios/suites: "beforeScenario" | "afterScenario" | "beforeSuite" | "afterSuite"
+	Mode      string            `json:"mode,omitempty" yaml:"mode,omitempty"` // process: "once" (default) | "persistent"
+	Cmd       string            `json:"cmd,omitempty" yaml:"cmd,omitempty"`
 	Args      []string          `json:"args,omitempty" yaml:"args,omitempty"`
 	TimeoutMs int               `json:"timeoutMs,omitempty" yaml:"timeoutMs,omitempty"`
 	Env       map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
-	Redact    []string          `json:"redact,omitempty" yaml:"redact,omitempty"`
+	Redact    []string          `json:"redact,omitempty" yaml:"redact,omitempty"` // names of vars this hook sets whose values are secret
+
+	// type: process — isolation (limits also apply to http)
+	Workdir        string   `json:"workdir,omitempty" yaml:"workdir,omitempty"`
+	InheritEnv     *bool    `json:"inherit_env,omitempty" yaml:"inherit_env,omitempty"`           // default true; false passes only PATH, pass_env and env
+	PassEnv        []string `json:"pass_env,omitempty" yaml:"pass_env,omitempty"`                 // runner env vars (* wildcards) passed when inherit_env is false
+	MaxInputBytes  int      `json:"max_input_bytes,omitempty" yaml:"max_input_bytes,omitempty"`   // encoded input; 0 = no limit
+	MaxOutputBytes int      `json:"max_output_bytes,omitempty" yaml:"max_output_bytes,omitempty"` // reply; 0 = 16MB
+
+	// type: http
+	URL     string            `json:"url,omitempty" yaml:"url,omitempty"` // may use ${VARS}
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Retries int               `json:"retries,omitempty" yaml:"retries,omitempty"` // extra attempts on network errors, 408, 429 and 5xx
+
+	// type: script (Starlark)
+	Script string `json:"script,omitempty" yaml:"script,omitempty"`
+	File   string `json:"file,omitempty" yaml:"file,omitempty"`
 }
@@ -0,0 +1,430 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+
+	"sea-qa/internal/contract"
+	"sea-qa/internal/generate"
+	"sea-qa/internal/sample"
+)
+
+type Options struct {
+	Validate bool        // answer 400 to requests that violate the spec
+	CORS     bool        // allow any origin and answer preflight requests
+	Log      *log.Logger // one line per request; nil = silent
+	Store    *Store      // enables stateful CRUD and the admin endpoints; nil = stateless
+}
+
+// Server answers requests for the operations in a spec with documented
+// examples or schema-generated data.
+type Server struct {
+	router routers.Router
+	bases  []string // server base paths stripped before routing
+	opt    Options
+}
+
+func New(v *contract.Validator, opt Options) (*Server, error) {
+	src := v.Doc()
+	var bases []string
+	for _, srv := range src.Servers {
+		if srv == nil {
+			continue
+		}
+		if p, err := srv.BasePath(); err == nil && p != "" && p != "/" {
+			bases = append(bases, strings.TrimSuffix(p, "/"))
+		}
+	}
+	// longest base first so /api/v2 wins over /api
+	sort.Slice(bases, func(i, j int) bool { return len(bases[i]) > len(bases[j]) })
+
+	// Route on paths only: the mock runs on its own host, not the spec's.
+	doc := *src
+	doc.Servers = nil
+	r, err := legacy.NewRouter(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("router: %w", err)
+	}
+	return &Server{router: r, bases: bases, opt: opt}, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sw := &statusWriter{ResponseWriter: w}
+	s.serve(sw, r)
+	if s.opt.Log != nil {
+		s.opt.Log.Printf("%s %s -> %d", r.Method, r.URL.RequestURI(), sw.status)
+	}
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if s.opt.CORS {
+		setCORS(w, r)
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	if s.opt.Store != nil && strings.HasPrefix(r.URL.Path, AdminPrefix) {
+		s.serveAdmin(w, r)
+		return
+	}
+
+	route, params, req, err := s.findRoute(r)
+	if err != nil {
+		var re *routers.RouteError
+		if errors.As(err, &re) && re.Reason == routers.ErrMethodNotAllowed.Error() {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed", "")
+			return
+		}
+		writeError(w, http.StatusNotFound, "no operation matches "+r.Method+" "+r.URL.Path, "")
+		return
+	}
+
+	pref, err := parsePrefer(r.Header.Get("Prefer"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad Prefer header", err.Error())
+		return
+	}
+
+	// Keep the body: validation consumes it and the store needs it.
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if s.opt.Validate {
+		in := &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: params,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+				MultiError:         true,
+			},
+		}
+		if err := openapi3filter.ValidateRequest(req.Context(), in); err != nil {
+			writeError(w, http.StatusBadRequest, "request does not match the spec", err.Error())
+			return
+		}
+	}
+
+	// An explicit Prefer wins over state.
+	if s.opt.Store != nil && pref == (Prefer{}) && s.stateful(w, req, route, body) {
+		return
+	}
+
+	resp, err := Select(route.Operation, pref, r.Header.Get("Accept"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "cannot satisfy Prefer", err.Error())
+		return
+	}
+	resp.Write(w)
+}
+
+// findRoute strips a server base path (if any) and routes the remainder.
+// The returned request carries the stripped path for validation.
+func (s *Server) findRoute(r *http.Request) (*routers.Route, map[string]string, *http.Request, error) {
+	candidates := []string{}
+	for _, b := range s.bases {
+		if r.URL.Path == b || strings.HasPrefix(r.URL.Path, b+"/") {
+			candidates = append(candidates, strings.TrimPrefix(r.URL.Path, b))
+		}
+	}
+	candidates = append(candidates, r.URL.Path)
+
+	var firstErr error
+	for _, p := range candidates {
+		if p == "" {
+			p = "/"
+		}
+		req := r.Clone(r.Context())
+		req.URL.Path = p
+		req.URL.RawPath = ""
+		route, params, err := s.router.FindRoute(req)
+		if err == nil {
+			return route, params, req, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, nil, nil, firstErr
+}
+
+// ---- Prefer ----
+
+// Prefer holds the supported `Prefer` header directives, e.g.
+// `Prefer: code=404, example=notFound` or `Prefer: dynamic=true`.
+type Prefer struct {
+	Code    int
+	Example string
+	Dynamic bool // generate from the schema even when examples exist
+}
+
+func parsePrefer(h string) (Prefer, error) {
+	var p Prefer
+	for _, part := range strings.FieldsFunc(h, func(r rune) bool { return r == ',' || r == ';' }) {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		v = strings.Trim(strings.TrimSpace(v), `"`)
+		switch strings.ToLower(strings.TrimSpace(k)) {
+		case "code":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 100 || n > 599 {
+				return p, fmt.Errorf("code=%q is not an HTTP status", v)
+			}
+			p.Code = n
+		case "example":
+			p.Example = v
+		case "dynamic":
+			p.Dynamic = v == "" || v == "true"
+		}
+	}
+	return p, nil
+}
+
+// ---- Response selection ----
+
+// Response is what the mock will send.
+type Response struct {
+	Status      int
+	ContentType string
+	Headers     map[string]string
+	Body        any // nil = no body
+}
+
+// Select picks the documented response for op: the Prefer code (exact, NXX,
+// then default), else the response documenting the Prefer example, else the
+// lowest 2xx. The body is the named example, or the media type's example,
+// or data generated from the schema.
+func Select(op *openapi3.Operation, pref Prefer, accept string) (*Response, error) {
+	key, status, err := pickStatus(op, pref)
+	if err != nil {
+		return nil, err
+	}
+	out := &Response{Status: status, Headers: map[string]string{}}
+	if key == "" {
+		return out, nil
+	}
+	rr := op.Responses.Value(key)
+	if rr == nil || rr.Value == nil {
+		return out, nil
+	}
+
+	for name, h := range rr.Value.Headers {
+		if h == nil || h.Value == nil || strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+		if v := headerValue(h.Value); v != "" {
+			out.Headers[name] = v
+		}
+	}
+
+	ct, mt := negotiate(rr.Value.Content, accept)
+	if mt == nil {
+		if pref.Example != "" {
+			return nil, fmt.Errorf("response %s has no content for example %q", key, pref.Example)
+		}
+		return out, nil
+	}
+	out.ContentType = ct
+	switch {
+	case pref.Example != "":
+		ex := mt.Examples[pref.Example]
+		if ex == nil || ex.Value == nil {
+			return nil, fmt.Errorf("example %q is not documented for response %s", pref.Example, key)
+		}
+		out.Body = ex.Value.Value
+	case pref.Dynamic && mt.Schema != nil && mt.Schema.Value != nil:
+		out.Body = sample.FromSchema(mt.Schema.Value, sample.Response)
+	default:
+		out.Body = sample.FromMediaType(mt, sample.Response)
+	}
+	return out, nil
+}
+
+func pickStatus(op *openapi3.Operation, pref Prefer) (string, int, error) {
+	if op.Responses == nil || op.Responses.Len() == 0 {
+		return "", http.StatusOK, nil
+	}
+	if pref.Code > 0 {
+		code := strconv.Itoa(pref.Code)
+		for _, k := range []string{code, code[:1] + "XX", code[:1] + "xx", "default"} {
+			if op.Responses.Value(k) != nil {
+				return k, pref.Code, nil
+			}
+		}
+		return "", 0, fmt.Errorf("status %d is not documented", pref.Code)
+	}
+
+	keys := make([]string, 0, op.Responses.Len())
+	for k := range op.Responses.Map() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys) // numeric codes sort before NXX and default
+
+	if pref.Example != "" {
+		for _, k := range keys {
+			rr := op.Responses.Value(k)
+			if rr == nil || rr.Value == nil {
+				continue
+			}
+			for _, mt := range rr.Value.Content {
+				if mt != nil && mt.Examples[pref.Example] != nil {
+					return k, keyStatus(k), nil
+				}
+			}
+		}
+		return "", 0, fmt.Errorf("no response documents example %q", pref.Example)
+	}
+
+	for _, k := range keys {
+		if n := keyStatus(k); n >= 200 && n < 300 {
+			return k, n, nil
+		}
+	}
+	if op.Responses.Value("default") != nil {
+		return "default", http.StatusOK, nil
+	}
+	return keys[0], keyStatus(keys[0]), nil
+}
+
+// keyStatus maps a response key to a status: "404" -> 404, "4XX" -> 400,
+// "default" -> 200.
+func keyStatus(k string) int {
+	if n, err := strconv.Atoi(k); err == nil {
+		return n
+	}
+	if len(k) == 3 && strings.EqualFold(k[1:], "XX") && k[0] >= '1' && k[0] <= '5' {
+		return int(k[0]-'0') * 100
+	}
+	return http.StatusOK
+}
+
+// negotiate honours an exact or wildcard Accept match, else prefers JSON.
+func negotiate(c openapi3.Content, accept string) (string, *openapi3.MediaType) {
+	if len(c) == 0 {
+		return "", nil
+	}
+	for _, part := range strings.Split(accept, ",") {
+		want, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if want == "" || want == "*/*" {
+			continue
+		}
+		if mt := c.Get(want); mt != nil {
+			return want, mt
+		}
+		if typ, ok := strings.CutSuffix(want, "/*"); ok {
+			names := make([]string, 0, len(c))
+			for n := range c {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			for _, n := range names {
+				if strings.HasPrefix(n, typ+"/") {
+					return n, c[n]
+				}
+			}
+		}
+	}
+	return generate.PickMediaType(c)
+}
+
+func headerValue(h *openapi3.Header) string {
+	var v any
+	switch {
+	case h.Example != nil:
+		v = h.Example
+	case h.Schema != nil && h.Schema.Value != nil:
+		v = sample.FromSchema(h.Schema.Value, sample.Response)
+	}
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// Write sends the response. Non-string bodies are JSON-encoded.
+func (resp *Response) Write(w http.ResponseWriter) {
+	for k, v := range resp.Headers {
+		w.Header().Set(k, v)
+	}
+	var data []byte
+	if resp.Body != nil && resp.Status != http.StatusNoContent && resp.Status != http.StatusNotModified {
+		if s, ok := resp.Body.(string); ok && !strings.Contains(resp.ContentType, "json") {
+			data = []byte(s)
+		} else {
+			b, err := json.Marshal(resp.Body)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, "encode mock body", err.Error())
+				return
+			}
+			data = b
+		}
+		w.Header().Set("Content-Type", resp.ContentType)
+	}
+	w.WriteHeader(resp.Status)
+	_, _ = w.Write(data)
+}
+
+// ---- helpers ----
+
+func writeError(w http.ResponseWriter, status int, msg, detail string) {
+	body := map[string]string{"error": msg}
+	if detail != "" {
+		body["detail"] = detail
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func setCORS(w http.ResponseWriter, r *http.Request) {
+	h := w.Header()
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		origin = "*"
+	}
+	h.Set("Access-Control-Allow-Origin", origin)
+	h.Set("Access-Control-Allow-Credentials", "true")
+	h.Set("Access-Control-Expose-Headers", "*")
+	if m := r.Header.Get("Access-Control-Request-Method"); m != "" {
+		h.Set("Access-Control-Allow-Methods", m)
+	}
+	if hdrs := r.Header.Get("Access-Control-Request-Headers"); hdrs != "" {
+		h.Set("Access-Control-Allow-Headers", hdrs)
+	}
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
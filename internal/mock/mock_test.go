@@ -0,0 +1,153 @@
+package mock_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sea-qa/internal/contract"
+	"sea-qa/internal/mock"
+)
+
+const petsSpec = `
+openapi: 3.0.3
+info: { title: Pets, version: "1" }
+servers: [{ url: "https://api.example.com/v1" }]
+paths:
+  /pets:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties: { name: { type: string } }
+      responses:
+        "201":
+          description: created
+          headers:
+            Location: { schema: { type: string, example: /v1/pets/1 } }
+          content:
+            application/json:
+              schema: { $ref: "#/components/schemas/Pet" }
+              examples:
+                rex: { value: { id: 1, name: Rex } }
+  /pets/{petId}:
+    get:
+      parameters:
+        - { name: petId, in: path, required: true, schema: { type: integer } }
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: { $ref: "#/components/schemas/Pet" }
+              example: { id: 7, name: Tom }
+        "404":
+          description: missing
+          content:
+            application/json:
+              schema: { type: object, properties: { message: { type: string } } }
+              examples:
+                notFound: { value: { message: no such pet } }
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [id, name]
+      properties:
+        id: { type: integer, minimum: 1 }
+        name: { type: string }
+`
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	v, err := contract.LoadFromBytes([]byte(petsSpec))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	m, err := mock.New(v, mock.Options{Validate: true})
+	if err != nil {
+		t.Fatalf("mock: %v", err)
+	}
+	srv := httptest.NewServer(m)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func do(t *testing.T, method, url, body string, hdr map[string]string) (int, http.Header, map[string]any) {
+	t.Helper()
+	var rd io.Reader
+	if body != "" {
+		rd = strings.NewReader(body)
+	}
+	req, _ := http.NewRequest(method, url, rd)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range hdr {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	var out map[string]any
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	return resp.StatusCode, resp.Header, out
+}
+
+func TestMock_RespondsWithExamples(t *testing.T) {
+	srv := newServer(t)
+
+	status, hdr, body := do(t, "POST", srv.URL+"/v1/pets", `{"name":"Rex"}`, nil)
+	if status != 201 || body["name"] != "Rex" || hdr.Get("Location") != "/v1/pets/1" {
+		t.Fatalf("POST: %d %v %v", status, hdr, body)
+	}
+
+	status, _, body = do(t, "GET", srv.URL+"/v1/pets/7", "", nil)
+	if status != 200 || body["name"] != "Tom" {
+		t.Fatalf("GET: %d %v", status, body)
+	}
+
+	status, _, body = do(t, "GET", srv.URL+"/pets/7", "", map[string]string{"Prefer": "code=404, example=notFound"})
+	if status != 404 || body["message"] != "no such pet" {
+		t.Fatalf("GET Prefer 404: %d %v", status, body)
+	}
+
+	status, _, body = do(t, "GET", srv.URL+"/pets/7", "", map[string]string{"Prefer": "example=notFound"})
+	if status != 404 {
+		t.Fatalf("GET Prefer example only: %d %v", status, body)
+	}
+
+	status, _, body = do(t, "GET", srv.URL+"/pets/7", "", map[string]string{"Prefer": "dynamic=true"})
+	if status != 200 || body["id"] != float64(1) || body["name"] != "string" {
+		t.Fatalf("GET dynamic: %d %v", status, body)
+	}
+}
+
+func TestMock_RejectsInvalidAndUnknown(t *testing.T) {
+	srv := newServer(t)
+
+	if status, _, body := do(t, "POST", srv.URL+"/pets", `{"nick":"x"}`, nil); status != 400 {
+		t.Fatalf("invalid body: %d %v", status, body)
+	}
+	if status, _, _ := do(t, "GET", srv.URL+"/pets/abc", "", nil); status != 400 {
+		t.Fatalf("invalid path param: %d", status)
+	}
+	if status, _, _ := do(t, "GET", srv.URL+"/nope", "", nil); status != 404 {
+		t.Fatalf("unknown path: %d", status)
+	}
+	if status, _, _ := do(t, "DELETE", srv.URL+"/pets", "", nil); status != 405 {
+		t.Fatalf("unknown method: %d", status)
+	}
+	if status, _, _ := do(t, "GET", srv.URL+"/pets/7", "", map[string]string{"Prefer": "code=500"}); status != 400 {
+		t.Fatalf("undocumented Prefer code: %d", status)
+	}
+}
@@ -0,0 +1,277 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// AdminPrefix is where the state endpoints live:
+//
+//	POST   /__admin/reset  back to the fixture state
+//	POST   /__admin/seed   add items: {"/users": [{...}]}
+//	GET    /__admin/state  dump every collection
+//	DELETE /__admin/state  drop everything, fixtures included
+const AdminPrefix = "/__admin/"
+
+func (s *Server) serveAdmin(w http.ResponseWriter, r *http.Request) {
+	switch strings.TrimPrefix(r.URL.Path, AdminPrefix) {
+	case "reset":
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed", "")
+			return
+		}
+		s.opt.Store.Reset()
+		w.WriteHeader(http.StatusNoContent)
+	case "seed":
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed", "")
+			return
+		}
+		var fx Fixtures
+		if err := json.NewDecoder(r.Body).Decode(&fx); err != nil {
+			writeError(w, http.StatusBadRequest, "seed body must be {\"/collection\": [items...]}", err.Error())
+			return
+		}
+		s.opt.Store.Seed(fx)
+		w.WriteHeader(http.StatusNoContent)
+	case "state":
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, s.opt.Store.State())
+		case http.MethodDelete:
+			s.opt.Store.Clear()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed", "")
+		}
+	default:
+		writeError(w, http.StatusNotFound, "unknown admin endpoint "+r.URL.Path, "")
+	}
+}
+
+// stateful handles CRUD-shaped operations against the store and reports
+// whether it wrote a response. Collection operations are those on a path
+// whose last segment is literal (POST creates, GET lists); item operations
+// end in a path parameter (GET, PUT, PATCH, DELETE).
+func (s *Server) stateful(w http.ResponseWriter, req *http.Request, route *routers.Route, body []byte) bool {
+	op := route.Operation
+	accept := req.Header.Get("Accept")
+	path := strings.TrimSuffix(req.URL.Path, "/")
+	param, isItem := lastParam(route.Path)
+
+	if !isItem {
+		switch req.Method {
+		case http.MethodPost:
+			var in map[string]any
+			if json.Unmarshal(body, &in) != nil || in == nil {
+				return false
+			}
+			resp, err := Select(op, Prefer{}, accept)
+			if err != nil {
+				return false
+			}
+			// Generated/example response fields fill in what the client did
+			// not send (ids, timestamps), but never the example's own id.
+			item := map[string]any{}
+			idField, idSchema := idFieldOf(responseSchema(op, resp.Status, resp.ContentType), "")
+			if m, ok := resp.Body.(map[string]any); ok {
+				for k, v := range m {
+					if k != idField {
+						item[k] = v
+					}
+				}
+			}
+			for k, v := range in {
+				item[k] = v
+			}
+			created := s.opt.Store.Create(path, idField, item, idGenerator(idSchema))
+			resp.Headers["Location"] = path + "/" + fmt.Sprint(created[idField])
+			resp.Body = created
+			resp.Write(w)
+			return true
+		case http.MethodGet:
+			resp, err := Select(op, Prefer{}, accept)
+			if err != nil {
+				return false
+			}
+			items := s.opt.Store.List(path)
+			body, ok := listBody(responseSchema(op, resp.Status, resp.ContentType), resp.Body, items)
+			if !ok {
+				return false
+			}
+			resp.Body = body
+			resp.Write(w)
+			return true
+		}
+		return false
+	}
+
+	coll := path[:strings.LastIndex(path, "/")]
+	id := path[strings.LastIndex(path, "/")+1:]
+	switch req.Method {
+	case http.MethodGet:
+		item, ok := s.opt.Store.Get(coll, id)
+		if !ok {
+			s.notFound(w, op, accept)
+			return true
+		}
+		return s.respondItem(w, op, accept, item)
+	case http.MethodPut:
+		var in map[string]any
+		if json.Unmarshal(body, &in) != nil || in == nil {
+			return false
+		}
+		idField, _ := idFieldOf(requestSchema(op), param)
+		return s.respondItem(w, op, accept, s.opt.Store.Put(coll, idField, id, in))
+	case http.MethodPatch:
+		var in map[string]any
+		if json.Unmarshal(body, &in) != nil || in == nil {
+			return false
+		}
+		item, ok := s.opt.Store.Patch(coll, id, in)
+		if !ok {
+			s.notFound(w, op, accept)
+			return true
+		}
+		return s.respondItem(w, op, accept, item)
+	case http.MethodDelete:
+		if !s.opt.Store.Delete(coll, id) {
+			s.notFound(w, op, accept)
+			return true
+		}
+		resp, err := Select(op, Prefer{}, accept)
+		if err != nil {
+			return false
+		}
+		resp.Write(w)
+		return true
+	}
+	return false
+}
+
+func (s *Server) respondItem(w http.ResponseWriter, op *openapi3.Operation, accept string, item map[string]any) bool {
+	resp, err := Select(op, Prefer{}, accept)
+	if err != nil {
+		return false
+	}
+	if resp.ContentType != "" {
+		resp.Body = item
+	}
+	resp.Write(w)
+	return true
+}
+
+// notFound answers with the documented 404 when there is one.
+func (s *Server) notFound(w http.ResponseWriter, op *openapi3.Operation, accept string) {
+	if resp, err := Select(op, Prefer{Code: http.StatusNotFound}, accept); err == nil {
+		resp.Write(w)
+		return
+	}
+	writeError(w, http.StatusNotFound, "not found", "")
+}
+
+func lastParam(tmpl string) (string, bool) {
+	seg := tmpl[strings.LastIndex(tmpl, "/")+1:]
+	if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+		return seg[1 : len(seg)-1], true
+	}
+	return "", false
+}
+
+// idFieldOf picks the id property: "id", else one named like the path
+// parameter, else "id".
+func idFieldOf(s *openapi3.Schema, param string) (string, *openapi3.Schema) {
+	if s == nil {
+		return "id", nil
+	}
+	for _, name := range []string{"id", param} {
+		if name == "" {
+			continue
+		}
+		if p := s.Properties[name]; p != nil {
+			return name, p.Value
+		}
+	}
+	return "id", nil
+}
+
+func idGenerator(s *openapi3.Schema) func(int) any {
+	switch {
+	case s != nil && s.Type.Includes(openapi3.TypeInteger):
+		return func(n int) any { return int64(n) }
+	case s != nil && s.Format == "uuid":
+		return func(int) any { return newUUID() }
+	}
+	return func(n int) any { return strconv.Itoa(n) }
+}
+
+// listBody renders items as the documented response: the array itself, or
+// the first array property of an object envelope.
+func listBody(s *openapi3.Schema, generated any, items []map[string]any) (any, bool) {
+	if s == nil {
+		return nil, false
+	}
+	arr := make([]any, 0, len(items))
+	for _, it := range items {
+		arr = append(arr, it)
+	}
+	if s.Type.Includes(openapi3.TypeArray) {
+		return arr, true
+	}
+	env, _ := generated.(map[string]any)
+	if env == nil {
+		env = map[string]any{}
+	}
+	names := make([]string, 0, len(s.Properties))
+	for n := range s.Properties {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if p := s.Properties[name]; p != nil && p.Value != nil && p.Value.Type.Includes(openapi3.TypeArray) {
+			env[name] = arr
+			return env, true
+		}
+	}
+	return nil, false
+}
+
+func responseSchema(op *openapi3.Operation, status int, ct string) *openapi3.Schema {
+	if op.Responses == nil || ct == "" {
+		return nil
+	}
+	rr := op.Responses.Status(status)
+	if rr == nil {
+		rr = op.Responses.Default()
+	}
+	if rr == nil || rr.Value == nil {
+		return nil
+	}
+	if mt := rr.Value.Content.Get(ct); mt != nil && mt.Schema != nil {
+		return mt.Schema.Value
+	}
+	return nil
+}
+
+func requestSchema(op *openapi3.Operation) *openapi3.Schema {
+	if op.RequestBody == nil || op.RequestBody.Value == nil {
+		return nil
+	}
+	if mt := op.RequestBody.Value.Content.Get("application/json"); mt != nil && mt.Schema != nil {
+		return mt.Schema.Value
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,146 @@
+package mock_test
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sea-qa/internal/contract"
+	"sea-qa/internal/mock"
+)
+
+const usersSpec = `
+openapi: 3.0.3
+info: { title: Users, version: "1" }
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          description: page
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  total: { type: integer }
+                  items: { type: array, items: { $ref: "#/components/schemas/User" } }
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: { type: object, required: [email], properties: { email: { type: string } } }
+      responses:
+        "201":
+          description: created
+          content:
+            application/json:
+              schema: { $ref: "#/components/schemas/User" }
+              example: { id: 99, email: x@example.com, role: member }
+  /users/{id}:
+    parameters:
+      - { name: id, in: path, required: true, schema: { type: integer } }
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: { $ref: "#/components/schemas/User" }
+        "404":
+          description: missing
+          content:
+            application/json:
+              schema: { type: object, properties: { message: { type: string } } }
+              example: { message: no such user }
+    patch:
+      requestBody:
+        content:
+          application/json:
+            schema: { type: object, properties: { role: { type: string } } }
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: { $ref: "#/components/schemas/User" }
+    delete:
+      responses:
+        "204": { description: gone }
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        id: { type: integer }
+        email: { type: string }
+        role: { type: string }
+`
+
+func TestMock_StatefulCRUDAndAdmin(t *testing.T) {
+	dir := t.TempDir()
+	fixture := filepath.Join(dir, "users.yaml")
+	if err := os.WriteFile(fixture, []byte("/users:\n  - { id: 1, email: ann@example.com, role: admin }\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fx, err := mock.LoadFixtures([]string{dir})
+	if err != nil {
+		t.Fatalf("fixtures: %v", err)
+	}
+	v, err := contract.LoadFromBytes([]byte(usersSpec))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	m, err := mock.New(v, mock.Options{Validate: true, Store: mock.NewStore(fx)})
+	if err != nil {
+		t.Fatalf("mock: %v", err)
+	}
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+
+	status, hdr, body := do(t, "POST", srv.URL+"/users", `{"email":"bob@example.com"}`, nil)
+	if status != 201 || body["id"] != float64(2) || body["email"] != "bob@example.com" || body["role"] != "member" {
+		t.Fatalf("create: %d %v", status, body)
+	}
+	if hdr.Get("Location") != "/users/2" {
+		t.Fatalf("Location = %q", hdr.Get("Location"))
+	}
+
+	if status, _, body = do(t, "GET", srv.URL+"/users/2", "", nil); status != 200 || body["email"] != "bob@example.com" {
+		t.Fatalf("get created: %d %v", status, body)
+	}
+	if status, _, body = do(t, "PATCH", srv.URL+"/users/2", `{"role":"admin"}`, nil); status != 200 || body["role"] != "admin" || body["email"] != "bob@example.com" {
+		t.Fatalf("patch: %d %v", status, body)
+	}
+	if status, _, body = do(t, "GET", srv.URL+"/users", "", nil); status != 200 || len(body["items"].([]any)) != 2 {
+		t.Fatalf("list: %d %v", status, body)
+	}
+	if status, _, _ = do(t, "DELETE", srv.URL+"/users/1", "", nil); status != 204 {
+		t.Fatalf("delete: %d", status)
+	}
+	if status, _, body = do(t, "GET", srv.URL+"/users/1", "", nil); status != 404 || body["message"] != "no such user" {
+		t.Fatalf("get deleted: %d %v", status, body)
+	}
+
+	// Prefer bypasses state
+	if status, _, body = do(t, "GET", srv.URL+"/users/1", "", map[string]string{"Prefer": "code=200"}); status != 200 {
+		t.Fatalf("prefer: %d %v", status, body)
+	}
+
+	// admin: reset restores fixtures, seed adds, state dumps
+	if status, _, _ = do(t, "POST", srv.URL+"/__admin/reset", "", nil); status != 204 {
+		t.Fatalf("reset: %d", status)
+	}
+	if status, _, _ = do(t, "POST", srv.URL+"/__admin/seed", `{"/users":[{"id":5,"email":"eve@example.com"}]}`, nil); status != 204 {
+		t.Fatalf("seed: %d", status)
+	}
+	status, _, body = do(t, "GET", srv.URL+"/__admin/state", "", nil)
+	if status != 200 || len(body["/users"].([]any)) != 2 {
+		t.Fatalf("state: %d %v", status, body)
+	}
+	if status, _, body = do(t, "GET", srv.URL+"/users/5", "", nil); status != 200 || body["email"] != "eve@example.com" {
+		t.Fatalf("get seeded: %d %v", status, body)
+	}
+}
@@ -0,0 +1,259 @@
+package mock
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fixtures maps a collection path (e.g. "/users") to its initial items.
+type Fixtures map[string][]map[string]any
+
+// LoadFixtures reads YAML/JSON fixture files (or every *.yaml, *.yml and
+// *.json file in a directory) and merges them in order:
+//
+//	/users:
+//	  - { id: u-1, email: ann@example.com }
+func LoadFixtures(paths []string) (Fixtures, error) {
+	out := Fixtures{}
+	for _, p := range paths {
+		files := []string{p}
+		if fi, err := os.Stat(p); err == nil && fi.IsDir() {
+			files = nil
+			for _, ext := range []string{"*.yaml", "*.yml", "*.json"} {
+				m, _ := filepath.Glob(filepath.Join(p, ext))
+				files = append(files, m...)
+			}
+			sort.Strings(files)
+		}
+		for _, f := range files {
+			b, err := os.ReadFile(f)
+			if err != nil {
+				return nil, fmt.Errorf("read fixtures: %w", err)
+			}
+			var fx Fixtures
+			if err := yaml.NewDecoder(bytes.NewReader(b)).Decode(&fx); err != nil {
+				return nil, fmt.Errorf("parse fixtures %s: %w", f, err)
+			}
+			for coll, items := range fx {
+				if !strings.HasPrefix(coll, "/") {
+					return nil, fmt.Errorf("fixtures %s: collection %q must be a path", f, coll)
+				}
+				out[coll] = append(out[coll], items...)
+			}
+		}
+	}
+	return out, nil
+}
+
+// Store is an in-memory resource store. Collections are keyed by their
+// concrete path ("/users", "/users/7/posts"); items by their id.
+type Store struct {
+	mu       sync.Mutex
+	fixtures Fixtures
+	colls    map[string]*collection
+}
+
+type collection struct {
+	order []string
+	items map[string]map[string]any
+	next  int
+}
+
+func NewStore(fx Fixtures) *Store {
+	s := &Store{fixtures: fx}
+	s.Reset()
+	return s
+}
+
+// Reset restores the fixture state.
+func (s *Store) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.colls = map[string]*collection{}
+	for coll, items := range s.fixtures {
+		for _, it := range items {
+			s.put(coll, "id", deepCopy(it))
+		}
+	}
+}
+
+// Clear drops every item, fixtures included.
+func (s *Store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.colls = map[string]*collection{}
+}
+
+// Seed adds (or replaces, by id) items.
+func (s *Store) Seed(fx Fixtures) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for coll, items := range fx {
+		for _, it := range items {
+			s.put(coll, "id", it)
+		}
+	}
+}
+
+// State returns a snapshot of every collection in insertion order.
+func (s *Store) State() Fixtures {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := Fixtures{}
+	for name := range s.colls {
+		out[name] = s.list(name)
+	}
+	return out
+}
+
+func (s *Store) List(coll string) []map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.list(coll)
+}
+
+func (s *Store) list(coll string) []map[string]any {
+	c := s.colls[coll]
+	out := []map[string]any{}
+	if c == nil {
+		return out
+	}
+	for _, id := range c.order {
+		out = append(out, deepCopy(c.items[id]))
+	}
+	return out
+}
+
+func (s *Store) Get(coll, id string) (map[string]any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.colls[coll]
+	if c == nil || c.items[id] == nil {
+		return nil, false
+	}
+	return deepCopy(c.items[id]), true
+}
+
+// Create stores item, assigning idField from newID(next sequence number)
+// when it is missing.
+func (s *Store) Create(coll, idField string, item map[string]any, newID func(seq int) any) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := item[idField]; !ok {
+		c := s.coll(coll)
+		c.next++
+		item[idField] = newID(c.next)
+	}
+	s.put(coll, idField, item)
+	return deepCopy(item)
+}
+
+// Put replaces (or creates) the item with the given id.
+func (s *Store) Put(coll, idField, id string, item map[string]any) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cur, ok := s.colls[coll]; ok && cur.items[id] != nil {
+		item[idField] = cur.items[id][idField]
+	} else if _, ok := item[idField]; !ok {
+		item[idField] = id
+	}
+	s.put(coll, idField, item)
+	return deepCopy(item)
+}
+
+// Patch merges patch into an existing item (top-level keys only).
+func (s *Store) Patch(coll, id string, patch map[string]any) (map[string]any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.colls[coll]
+	if c == nil || c.items[id] == nil {
+		return nil, false
+	}
+	for k, v := range patch {
+		c.items[id][k] = v
+	}
+	return deepCopy(c.items[id]), true
+}
+
+func (s *Store) Delete(coll, id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.colls[coll]
+	if c == nil || c.items[id] == nil {
+		return false
+	}
+	delete(c.items, id)
+	for i, o := range c.order {
+		if o == id {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func (s *Store) coll(name string) *collection {
+	c := s.colls[name]
+	if c == nil {
+		c = &collection{items: map[string]map[string]any{}}
+		s.colls[name] = c
+	}
+	return c
+}
+
+func (s *Store) put(coll, idField string, item map[string]any) {
+	c := s.coll(coll)
+	v, ok := item[idField]
+	if !ok {
+		c.next++
+		v = strconv.Itoa(c.next)
+		item[idField] = v
+	}
+	id := fmt.Sprint(v)
+	if n, err := strconv.Atoi(id); err == nil && n > c.next {
+		c.next = n
+	}
+	if c.items[id] == nil {
+		c.order = append(c.order, id)
+	}
+	c.items[id] = item
+}
+
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func deepCopy(m map[string]any) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = copyValue(v)
+	}
+	return out
+}
+
+func copyValue(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		return deepCopy(x)
+	case []any:
+		out := make([]any, len(x))
+		for i, e := range x {
+			out[i] = copyValue(e)
+		}
+		return out
+	}
+	return v
+}
@@ -41,6 +41,20 @@ func (p *Parser) ParseBytes(b []byte) (*ir.TestSuite, error) {
 	return &suite, nil
 }
 
+// Marshal renders a suite as YAML that ParseBytes accepts (used by generators/importers).
+func Marshal(suite *ir.TestSuite) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(suite); err != nil {
+		return nil, fmt.Errorf("encode: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("encode: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
 // --- validation helpers ---
 
 func validateSuite(s *ir.TestSuite) error {
@@ -50,6 +64,21 @@ func validateSuite(s *ir.TestSuite) error {
 	if len(s.Scenarios) == 0 {
 		return wrapValidation("suite.scenarios must not be empty")
 	}
+	for name, spec := range s.OpenAPIs {
+		if name == ir.DefaultSpec {
+			return wrapValidation(fmt.Sprintf("openapis.%s: the name is reserved for the openapi/--openapi spec", name))
+		}
+		if spec.File == "" {
+			return wrapValidation(fmt.Sprintf("openapis.%s.file must not be empty", name))
+		}
+		if spec.Host == "" && spec.BaseURL == "" && spec.Prefix == "" {
+			return wrapValidation(fmt.Sprintf("openapis.%s needs host, base_url or prefix", name))
+		}
+	}
+	// suite hooks may also wrap every scenario
+	if err := validateHooks(s.Hooks, "suite", "beforeSuite", "afterSuite", "beforeScenario", "afterScenario"); err != nil {
+		return err
+	}
 	for i := range s.Scenarios {
 		if err := validateScenario(&s.Scenarios[i], i); err != nil {
 			return err
@@ -65,6 +94,9 @@ func validateScenario(sc *ir.Scenario, idx int) error {
 	if len(sc.Steps) == 0 {
 		return wrapValidation(fmt.Sprintf("scenario[%d].steps must not be empty", idx))
 	}
+	if err := validateHooks(sc.Hooks, fmt.Sprintf("scenario[%d]", idx), "beforeScenario", "afterScenario"); err != nil {
+		return err
+	}
 	for j := range sc.Steps {
 		if err := validateStep(&sc.Steps[j], idx, j); err != nil {
 			return err
@@ -80,6 +112,24 @@ func validateStep(st *ir.Step, i, j int) error {
 	if st.Request.URL == "" {
 		return wrapValidation(fmt.Sprintf("scenario[%d].step[%d].request.url must not be empty", i, j))
 	}
+	return validateHooks(st.Hooks, fmt.Sprintf("scenario[%d].step[%d]", i, j), "before", "after")
+}
+
+// validateHooks rejects hooks whose when is not run at this level; the
+// runner would silently skip them.
+func validateHooks(hs []ir.Hook, at string, allowed ...string) error {
+	for k, h := range hs {
+		ok := false
+		for _, a := range allowed {
+			if strings.EqualFold(h.When, a) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return wrapValidation(fmt.Sprintf("%s.hooks[%d].when %q must be one of %s", at, k, h.When, strings.Join(allowed, ", ")))
+		}
+	}
 	return nil
 }
 
@@ -2,6 +2,8 @@ package parser_test
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -112,3 +114,64 @@ func TestParse_KnownFieldsEnforced(t *testing.T) {
 		t.Fatal("expected error for unknown field, got nil")
 	}
 }
+
+func TestParse_Validation_HookPhase(t *testing.T) {
+	const tmpl = `
+name: Foo
+%s
+scenarios:
+  - name: Bar
+%s
+    steps:
+      - request:
+          method: GET
+          url: http://localhost:8080
+%s
+`
+	hook := func(indent, when string) string {
+		return fmt.Sprintf("%shooks:\n%s  - when: %s\n%s    type: script\n%s    script: pass", indent, indent, when, indent, indent)
+	}
+	cases := []struct {
+		name                string
+		suite, scenario, st string
+		wantErr             string
+	}{
+		{"suite ok", hook("", "beforeScenario"), "", "", ""},
+		{"scenario ok", "", hook("    ", "afterscenario"), "", ""},
+		{"step ok", "", "", hook("        ", "after"), ""},
+		{"suite before", hook("", "before"), "", "", `suite.hooks[0].when "before"`},
+		{"scenario beforeSuite", "", hook("    ", "beforeSuite"), "", `scenario[0].hooks[0].when "beforeSuite"`},
+		{"scenario before", "", hook("    ", "before"), "", `scenario[0].hooks[0].when "before"`},
+		{"step beforeScenario", "", "", hook("        ", "beforeScenario"), `scenario[0].step[0].hooks[0].when "beforeScenario"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parser.New().ParseBytes([]byte(fmt.Sprintf(tmpl, tc.suite, tc.scenario, tc.st)))
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, parser.ErrValidation) || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("err = %v, want validation error containing %s", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestParse_Validation_ReservedSpecName(t *testing.T) {
+	const doc = `
+name: Foo
+openapis:
+  default: { file: a.yaml, host: a.local }
+scenarios:
+  - name: Bar
+    steps:
+      - request: { method: GET, url: http://a.local }
+`
+	_, err := parser.New().ParseBytes([]byte(doc))
+	if !errors.Is(err, parser.ErrValidation) || !strings.Contains(err.Error(), "openapis.default") {
+		t.Fatalf("err = %v, want reserved name error", err)
+	}
+}
@@ -0,0 +1,496 @@
+package postman
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sea-qa/internal/capture"
+	"sea-qa/internal/ir"
+)
+
+// ---- Postman v2.1 model (the subset we translate) ----
+
+type Collection struct {
+	Info     Info       `json:"info"`
+	Item     []Item     `json:"item"`
+	Variable []Variable `json:"variable"`
+	Auth     *Auth      `json:"auth"`
+	Event    []Event    `json:"event"`
+}
+
+type Info struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+// Item is either a folder (Item set) or a request (Request set).
+type Item struct {
+	Name    string   `json:"name"`
+	Item    []Item   `json:"item"`
+	Request *Request `json:"request"`
+	Event   []Event  `json:"event"`
+	Auth    *Auth    `json:"auth"`
+}
+
+type Request struct {
+	Method string `json:"method"`
+	URL    URL    `json:"url"`
+	Header []KV   `json:"header"`
+	Body   *Body  `json:"body"`
+	Auth   *Auth  `json:"auth"`
+}
+
+// UnmarshalJSON accepts the short form where a request is just its URL.
+func (r *Request) UnmarshalJSON(b []byte) error {
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		*r = Request{Method: "GET", URL: URL{Raw: s}}
+		return nil
+	}
+	type plain Request
+	var p plain
+	if err := json.Unmarshal(b, &p); err != nil {
+		return err
+	}
+	*r = Request(p)
+	return nil
+}
+
+type URL struct {
+	Raw   string `json:"raw"`
+	Query []KV   `json:"query"`
+}
+
+// UnmarshalJSON accepts both "url": "..." and "url": {"raw": "..."}.
+func (u *URL) UnmarshalJSON(b []byte) error {
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		*u = URL{Raw: s}
+		return nil
+	}
+	type plain URL
+	var p plain
+	if err := json.Unmarshal(b, &p); err != nil {
+		return err
+	}
+	*u = URL(p)
+	return nil
+}
+
+type KV struct {
+	Key      string `json:"key"`
+	Value    any    `json:"value"`
+	Disabled bool   `json:"disabled"`
+	Type     string `json:"type"`
+}
+
+func (kv KV) String() string {
+	switch v := kv.Value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+type Body struct {
+	Mode       string `json:"mode"`
+	Raw        string `json:"raw"`
+	URLEncoded []KV   `json:"urlencoded"`
+	FormData   []KV   `json:"formdata"`
+	GraphQL    *struct {
+		Query     string `json:"query"`
+		Variables string `json:"variables"`
+	} `json:"graphql"`
+	Options struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+	Disabled bool `json:"disabled"`
+}
+
+type Auth struct {
+	Type   string `json:"type"`
+	Bearer []KV   `json:"bearer"`
+	Basic  []KV   `json:"basic"`
+	APIKey []KV   `json:"apikey"`
+}
+
+func (a *Auth) param(list []KV, key string) string {
+	for _, kv := range list {
+		if kv.Key == key {
+			return kv.String()
+		}
+	}
+	return ""
+}
+
+type Event struct {
+	Listen string `json:"listen"` // "test" | "prerequest"
+	Script Script `json:"script"`
+}
+
+type Script struct {
+	Exec Lines `json:"exec"`
+}
+
+// Lines accepts a script as a single string or as an array of lines.
+type Lines []string
+
+func (l *Lines) UnmarshalJSON(b []byte) error {
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		*l = strings.Split(s, "\n")
+		return nil
+	}
+	var arr []string
+	if err := json.Unmarshal(b, &arr); err != nil {
+		return err
+	}
+	*l = arr
+	return nil
+}
+
+type Variable struct {
+	Key      string `json:"key"`
+	Value    any    `json:"value"`
+	Disabled bool   `json:"disabled"`
+}
+
+// Environment is an exported Postman environment.
+type Environment struct {
+	Name   string `json:"name"`
+	Values []struct {
+		Key     string `json:"key"`
+		Value   any    `json:"value"`
+		Enabled *bool  `json:"enabled"`
+	} `json:"values"`
+}
+
+// Note records something that was not (fully) translated.
+type Note struct {
+	Item   string // "Folder / Request", or "" for the collection itself
+	Reason string
+}
+
+func LoadCollection(path string) (*Collection, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read collection: %w", err)
+	}
+	var c Collection
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("parse collection: %w", err)
+	}
+	if c.Info.Schema != "" && !strings.Contains(c.Info.Schema, "v2.1") && !strings.Contains(c.Info.Schema, "v2.0") {
+		return nil, fmt.Errorf("unsupported collection schema %q (export as v2.1)", c.Info.Schema)
+	}
+	return &c, nil
+}
+
+func LoadEnvironment(path string) (*Environment, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read environment: %w", err)
+	}
+	var e Environment
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, fmt.Errorf("parse environment: %w", err)
+	}
+	return &e, nil
+}
+
+// ---- Conversion ----
+
+// Vars returns the enabled environment values as a flat map suitable for
+// an env JSON file (see vars.LoadJSONFiles).
+func (e *Environment) Vars() map[string]string {
+	out := map[string]string{}
+	for _, v := range e.Values {
+		if v.Enabled != nil && !*v.Enabled {
+			continue
+		}
+		out[v.Key] = KV{Value: v.Value}.String()
+	}
+	return out
+}
+
+// Vars returns the collection-level variables; they have no place in a
+// suite, so callers merge them into the env file.
+func (c *Collection) Vars() map[string]string {
+	out := map[string]string{}
+	for _, v := range c.Variable {
+		if !v.Disabled {
+			out[v.Key] = KV{Value: v.Value}.String()
+		}
+	}
+	return out
+}
+
+// ToSuite converts folders to scenarios and requests to steps. Requests at
+// the top level form a scenario named after the collection; nested folders
+// are flattened into "Parent / Child" scenarios.
+func ToSuite(c *Collection) (*ir.TestSuite, []Note) {
+	cv := &converter{}
+	name := c.Info.Name
+	if name == "" {
+		name = "postman import"
+	}
+	if len(c.Event) > 0 {
+		cv.events("", c.Event, nil)
+	}
+	cv.folder(name, "", c.Item, c.Auth)
+	return &ir.TestSuite{Name: name, Scenarios: cv.scenarios}, cv.notes
+}
+
+type converter struct {
+	scenarios []ir.Scenario
+	notes     []Note
+}
+
+func (cv *converter) note(item, format string, a ...any) {
+	cv.notes = append(cv.notes, Note{Item: item, Reason: fmt.Sprintf(format, a...)})
+}
+
+func (cv *converter) folder(scName, prefix string, items []Item, auth *Auth) {
+	sc := ir.Scenario{Name: scName, Tags: []string{"postman"}}
+	var sub []Item
+	for _, it := range items {
+		if it.Request == nil {
+			sub = append(sub, it)
+			continue
+		}
+		path := join(prefix, it.Name)
+		step := ir.Step{Name: it.Name, Request: cv.request(path, it.Request, inherit(it.Auth, auth))}
+		cv.events(path, it.Event, &step)
+		sc.Steps = append(sc.Steps, step)
+	}
+	if len(sc.Steps) > 0 {
+		cv.scenarios = append(cv.scenarios, sc)
+	}
+	for _, f := range sub {
+		path := join(prefix, f.Name)
+		if len(f.Event) > 0 {
+			cv.note(path, "folder-level scripts are not translated")
+		}
+		cv.folder(path, path, f.Item, inherit(f.Auth, auth))
+	}
+}
+
+func (cv *converter) request(item string, r *Request, auth *Auth) ir.Request {
+	method := strings.ToUpper(r.Method)
+	if method == "" {
+		method = "GET"
+	}
+	out := ir.Request{Method: method, URL: cv.vars(item, rawURL(r.URL))}
+	set := func(k, v string) {
+		if out.Headers == nil {
+			out.Headers = map[string]string{}
+		}
+		out.Headers[k] = cv.vars(item, v)
+	}
+	// credentials become vars unless they already use one
+	secret := func(k, v string) {
+		set(k, v)
+		out.Headers[k] = capture.CredentialRef(k, out.Headers[k])
+	}
+	for _, h := range r.Header {
+		if h.Disabled || !capture.KeepHeader(h.Key) {
+			continue
+		}
+		if capture.Credential(h.Key) {
+			secret(h.Key, h.String())
+		} else {
+			set(h.Key, h.String())
+		}
+	}
+	if a := inherit(r.Auth, auth); a != nil {
+		switch a.Type {
+		case "", "noauth":
+		case "bearer":
+			secret("Authorization", "Bearer "+a.param(a.Bearer, "token"))
+		case "basic":
+			user, pass := a.param(a.Basic, "username"), a.param(a.Basic, "password")
+			if strings.Contains(user+pass, "{{") {
+				// variables expand after the header is built, so it cannot be encoded here
+				cv.note(item, "basic auth uses variables: set the Authorization header by hand")
+			} else {
+				secret("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(user+":"+pass)))
+			}
+		case "apikey":
+			if a.param(a.APIKey, "in") == "query" {
+				cv.note(item, "apikey auth in query: add %q to the URL", a.param(a.APIKey, "key"))
+			} else {
+				secret(a.param(a.APIKey, "key"), a.param(a.APIKey, "value"))
+			}
+		default:
+			cv.note(item, "auth type %q is not translated", a.Type)
+		}
+	}
+	if b := r.Body; b != nil && !b.Disabled {
+		switch b.Mode {
+		case "", "none":
+		case "raw":
+			if b.Raw != "" {
+				out.Body = capture.ParseBody(cv.vars(item, b.Raw))
+				if _, ok := out.Body.(string); !ok && headerless(out.Headers, "Content-Type") {
+					set("Content-Type", "application/json")
+				}
+			}
+		case "urlencoded":
+			form := url.Values{}
+			for _, kv := range b.URLEncoded {
+				if !kv.Disabled {
+					form.Add(kv.Key, kv.String())
+				}
+			}
+			// keep ${var} readable rather than percent-encoded
+			out.Body = strings.NewReplacer("%24%7B", "${", "%7D", "}").Replace(cv.vars(item, form.Encode()))
+			if headerless(out.Headers, "Content-Type") {
+				set("Content-Type", "application/x-www-form-urlencoded")
+			}
+		case "graphql":
+			if b.GraphQL != nil {
+				body := map[string]any{"query": b.GraphQL.Query}
+				if vs := strings.TrimSpace(b.GraphQL.Variables); vs != "" {
+					var v any
+					if json.Unmarshal([]byte(vs), &v) == nil {
+						body["variables"] = v
+					} else {
+						cv.note(item, "graphql variables are not valid JSON")
+					}
+				}
+				out.Body = capture.ParseBody(cv.vars(item, mustJSON(body)))
+				if headerless(out.Headers, "Content-Type") {
+					set("Content-Type", "application/json")
+				}
+			}
+		default:
+			cv.note(item, "body mode %q is not translated", b.Mode)
+		}
+	}
+	return out
+}
+
+// ---- Variables ----
+
+var (
+	varRe      = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+	dynamicVar = map[string]string{
+		"$guid": "uuid", "$randomUUID": "uuid", "$isoTimestamp": "now",
+	}
+)
+
+// vars rewrites {{name}} to ${name}; Postman dynamic variables map to the
+// runner built-ins where one exists.
+func (cv *converter) vars(item, s string) string {
+	return varRe.ReplaceAllStringFunc(s, func(m string) string {
+		name := varRe.FindStringSubmatch(m)[1]
+		if strings.HasPrefix(name, "$") {
+			if b, ok := dynamicVar[name]; ok {
+				return "${" + b + "}"
+			}
+			cv.note(item, "dynamic variable {{%s}} has no equivalent", name)
+			return m
+		}
+		return "${" + name + "}"
+	})
+}
+
+// ---- Scripts ----
+
+var (
+	haveStatusRe = regexp.MustCompile(`pm\.response\.to\.have\.status\(\s*(\d{3})\s*\)`)
+	codeEqualRe  = regexp.MustCompile(`pm\.expect\(\s*pm\.response\.(?:code|status)\s*\)\.to\.(?:be\.)?(?:eql|equal|equals|eq)\(\s*(\d{3})\s*\)`)
+	// structural lines that carry no assertion of their own
+	scaffoldRe = regexp.MustCompile(`^(pm\.test\(.*function\s*\(\s*\)\s*\{|pm\.test\(.*\(\)\s*=>\s*\{|\}\);?|\{|\}|//.*)$`)
+)
+
+// events turns status assertions in test scripts into expectations and
+// notes every other script line.
+func (cv *converter) events(item string, evs []Event, step *ir.Step) {
+	for _, ev := range evs {
+		var untranslated int
+		for _, line := range ev.Script.Exec {
+			line = strings.TrimSpace(line)
+			if line == "" || scaffoldRe.MatchString(line) {
+				continue
+			}
+			if ev.Listen == "test" && step != nil {
+				if code := statusOf(line); code != 0 {
+					step.Expect = append(step.Expect, ir.Expectation{Type: ir.ExpectStatus, Value: code})
+					continue
+				}
+			}
+			untranslated++
+		}
+		if untranslated > 0 {
+			what := ev.Listen + " script"
+			if item == "" {
+				what = "collection " + what
+			}
+			cv.note(item, "%s: %d line(s) not translated", what, untranslated)
+		}
+	}
+}
+
+func statusOf(line string) int {
+	for _, re := range []*regexp.Regexp{haveStatusRe, codeEqualRe} {
+		if m := re.FindStringSubmatch(line); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			return n
+		}
+	}
+	return 0
+}
+
+// ---- Helpers ----
+
+func rawURL(u URL) string {
+	raw := u.Raw
+	// disabled query params are still part of raw; drop them
+	for _, q := range u.Query {
+		if q.Disabled {
+			raw = strings.Replace(raw, q.Key+"="+q.String(), "", 1)
+		}
+	}
+	raw = strings.NewReplacer("?&", "?", "&&", "&").Replace(raw)
+	return strings.TrimRight(raw, "?&")
+}
+
+func inherit(own, parent *Auth) *Auth {
+	if own != nil {
+		return own
+	}
+	return parent
+}
+
+func headerless(h map[string]string, key string) bool {
+	for k := range h {
+		if strings.EqualFold(k, key) {
+			return false
+		}
+	}
+	return true
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + " / " + name
+}
+
+func mustJSON(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,187 @@
+package postman_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sea-qa/internal/executor"
+	"sea-qa/internal/parser"
+	"sea-qa/internal/postman"
+	"sea-qa/internal/vars"
+)
+
+const collection = `{
+  "info": {"name": "Users", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+  "auth": {"type": "bearer", "bearer": [{"key": "token", "value": "{{token}}"}]},
+  "variable": [{"key": "baseUrl", "value": "http://unused"}],
+  "item": [
+    {"name": "Health", "request": "{{baseUrl}}/health"},
+    {"name": "Users", "item": [
+      {"name": "Create", "request": {
+          "method": "POST",
+          "url": {"raw": "{{baseUrl}}/users"},
+          "header": [{"key": "X-Trace", "value": "{{$guid}}"}, {"key": "X-Off", "value": "1", "disabled": true}],
+          "body": {"mode": "raw", "raw": "{\"email\": \"{{email}}\"}", "options": {"raw": {"language": "json"}}}
+        },
+        "event": [{"listen": "test", "script": {"exec": [
+          "pm.test(\"created\", function () {",
+          "    pm.response.to.have.status(201);",
+          "});",
+          "pm.environment.set(\"id\", pm.response.json().id);"
+        ]}}]
+      },
+      {"name": "Admin", "item": [
+        {"name": "Get", "request": {"method": "GET", "url": "{{baseUrl}}/users/1?x={{$randomInt}}", "auth": {"type": "oauth2"}},
+         "event": [{"listen": "test", "script": {"exec": "pm.expect(pm.response.code).to.eql(200);"}}]}
+      ]}
+    ]}
+  ]
+}`
+
+const environment = `{"name": "dev", "values": [
+  {"key": "baseUrl", "value": "BASE", "enabled": true},
+  {"key": "token", "value": "t0k", "enabled": true},
+  {"key": "email", "value": "a@example.com"},
+  {"key": "off", "value": "x", "enabled": false}
+]}`
+
+func TestImport_CollectionAndEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	cp, ep := filepath.Join(dir, "c.json"), filepath.Join(dir, "e.json")
+	_ = os.WriteFile(cp, []byte(collection), 0o644)
+	_ = os.WriteFile(ep, []byte(environment), 0o644)
+
+	c, err := postman.LoadCollection(cp)
+	if err != nil {
+		t.Fatalf("collection: %v", err)
+	}
+	suite, notes := postman.ToSuite(c)
+	data, err := parser.Marshal(suite)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	parsed, err := parser.New().ParseBytes(data)
+	if err != nil {
+		t.Fatalf("parse:\n%s\n%v", data, err)
+	}
+
+	var names []string
+	for _, sc := range parsed.Scenarios {
+		names = append(names, sc.Name)
+	}
+	if got := strings.Join(names, "|"); got != "Users|Users|Users / Admin" {
+		t.Fatalf("scenarios = %q", got)
+	}
+	health := parsed.Scenarios[0].Steps[0].Request
+	if health.Method != "GET" || health.URL != "${baseUrl}/health" || health.Headers["Authorization"] != "Bearer ${token}" {
+		t.Fatalf("health = %+v", health)
+	}
+	create := parsed.Scenarios[1].Steps[0]
+	if create.Request.Headers["X-Trace"] != "${uuid}" || create.Request.Headers["X-Off"] != "" {
+		t.Fatalf("create headers = %v", create.Request.Headers)
+	}
+	if b, ok := create.Request.Body.(map[string]any); !ok || b["email"] != "${email}" {
+		t.Fatalf("create body = %#v", create.Request.Body)
+	}
+	if len(create.Expect) != 1 || create.Expect[0].Value != 201 {
+		t.Fatalf("create expect = %+v", create.Expect)
+	}
+	get := parsed.Scenarios[2].Steps[0]
+	if len(get.Expect) != 1 || get.Expect[0].Value != 200 {
+		t.Fatalf("get expect = %+v", get.Expect)
+	}
+	if _, ok := get.Request.Headers["Authorization"]; ok {
+		t.Fatalf("oauth2 request inherited bearer: %v", get.Request.Headers)
+	}
+
+	var reasons []string
+	for _, n := range notes {
+		reasons = append(reasons, n.Item+": "+n.Reason)
+	}
+	all := strings.Join(reasons, "\n")
+	for _, want := range []string{
+		"Users / Create: test script: 1 line(s) not translated",
+		`Users / Admin / Get: auth type "oauth2"`,
+		"Users / Admin / Get: dynamic variable {{$randomInt}}",
+	} {
+		if !strings.Contains(all, want) {
+			t.Fatalf("notes missing %q:\n%s", want, all)
+		}
+	}
+
+	e, err := postman.LoadEnvironment(ep)
+	if err != nil {
+		t.Fatalf("environment: %v", err)
+	}
+	env := e.Vars()
+	if env["token"] != "t0k" || env["email"] != "a@example.com" || env["off"] != "" {
+		t.Fatalf("env = %v", env)
+	}
+
+	// the converted suite runs with the converted environment
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Header.Get("Authorization") != "Bearer t0k" && r.URL.Path != "/users/1":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.Method == "POST":
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer srv.Close()
+	env["baseUrl"] = srv.URL
+	b, _ := json.Marshal(env)
+	envPath := filepath.Join(dir, "env.json")
+	_ = os.WriteFile(envPath, b, 0o644)
+	loaded, err := vars.LoadJSONFiles([]string{envPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed.Scenarios[2].Steps[0].Request.URL = "${baseUrl}/users/1"
+	res, err := executor.NewWithVars(loaded).RunSuite(context.Background(), parsed)
+	if err != nil || !res.Passed {
+		t.Fatalf("run: %v %+v", err, res)
+	}
+}
+
+func TestImport_LiteralCredentials(t *testing.T) {
+	const c = `{
+  "info": {"name": "Keys"},
+  "item": [
+    {"name": "Key", "request": {"url": "http://x/a", "auth": {"type": "apikey", "apikey": [{"key": "key", "value": "X-Api-Key"}, {"key": "value", "value": "k3y"}]}}},
+    {"name": "Basic", "request": {"url": "http://x/b", "auth": {"type": "basic", "basic": [{"key": "username", "value": "ann"}, {"key": "password", "value": "pw"}]}}},
+    {"name": "Header", "request": {"url": "http://x/c", "header": [{"key": "Authorization", "value": "Bearer lit3ral"}]}}
+  ]
+}`
+	p := filepath.Join(t.TempDir(), "c.json")
+	_ = os.WriteFile(p, []byte(c), 0o644)
+	col, err := postman.LoadCollection(p)
+	if err != nil {
+		t.Fatalf("collection: %v", err)
+	}
+	suite, _ := postman.ToSuite(col)
+	data, err := parser.Marshal(suite)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	steps := suite.Scenarios[0].Steps
+	if got := steps[0].Request.Headers["X-Api-Key"]; got != "${X_API_KEY}" {
+		t.Errorf("apikey = %q", got)
+	}
+	if got := steps[1].Request.Headers["Authorization"]; got != "Basic ${TOKEN}" {
+		t.Errorf("basic = %q", got)
+	}
+	if got := steps[2].Request.Headers["Authorization"]; got != "Bearer ${TOKEN}" {
+		t.Errorf("header = %q", got)
+	}
+	for _, secret := range []string{"k3y", "YW5uOnB3", "lit3ral"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("suite contains %q:\n%s", secret, data)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package record
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"sea-qa/internal/capture"
+	"sea-qa/internal/ir"
+	"sea-qa/internal/redact"
+)
+
+// Exchange is one proxied request/response pair.
+type Exchange struct {
+	Method      string              `json:"method"`
+	Path        string              `json:"path"` // path plus raw query
+	ReqHeaders  map[string]string   `json:"reqHeaders,omitempty"`
+	ReqBody     string              `json:"reqBody,omitempty"`
+	Status      int                 `json:"status"`
+	RespHeaders map[string][]string `json:"respHeaders,omitempty"`
+	RespBody    string              `json:"respBody,omitempty"`
+}
+
+// Cassette is the on-disk form of a recording, served back by Replayer.
+type Cassette struct {
+	Target    string     `json:"target"`
+	Exchanges []Exchange `json:"exchanges"`
+}
+
+// Recorder is a reverse proxy to Target that keeps every exchange.
+type Recorder struct {
+	target *url.URL
+	client *http.Client
+
+	mu        sync.Mutex
+	exchanges []Exchange
+	origins   map[string]bool // proxy origins seen, replaced by ${BASE_URL}
+}
+
+func New(target string) (*Recorder, error) {
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("target must be an absolute URL, got %q", target)
+	}
+	return &Recorder{
+		target: u,
+		client: &http.Client{
+			Timeout: 60 * time.Second,
+			// hand redirects back to the client so each hop is recorded
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		origins: map[string]bool{},
+	}, nil
+}
+
+func (rec *Recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	reqBody, _ := io.ReadAll(r.Body)
+
+	u := *rec.target
+	u.Path = strings.TrimSuffix(rec.target.Path, "/") + r.URL.Path
+	u.RawQuery = r.URL.RawQuery
+	out, err := http.NewRequestWithContext(r.Context(), r.Method, u.String(), bytes.NewReader(reqBody))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	for k, vs := range r.Header {
+		if !capture.HopByHop(k) {
+			out.Header[k] = vs
+		}
+	}
+	// Left to the transport, compression is negotiated and decoded for us,
+	// so recorded bodies are plain.
+	out.Header.Del("Accept-Encoding")
+	resp, err := rec.client.Do(out)
+	if err != nil {
+		http.Error(w, "record: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+
+	for k, vs := range resp.Header {
+		if !capture.HopByHop(k) {
+			w.Header()[k] = vs
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(respBody)
+
+	ex := Exchange{
+		Method:      r.Method,
+		Path:        r.URL.RequestURI(),
+		ReqHeaders:  map[string]string{},
+		ReqBody:     string(reqBody),
+		Status:      resp.StatusCode,
+		RespHeaders: map[string][]string{},
+		RespBody:    string(respBody),
+	}
+	for k := range r.Header {
+		ex.ReqHeaders[k] = maskCredential(k, r.Header.Get(k))
+	}
+	for k, vs := range resp.Header {
+		masked := make([]string, len(vs))
+		for i, v := range vs {
+			masked[i] = maskCredential(k, v)
+		}
+		ex.RespHeaders[k] = masked
+	}
+	rec.mu.Lock()
+	rec.exchanges = append(rec.exchanges, ex)
+	if r.Host != "" {
+		rec.origins["http://"+r.Host] = true
+	}
+	rec.mu.Unlock()
+}
+
+// Cassette snapshots what has been recorded so far.
+func (rec *Recorder) Cassette() *Cassette {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return &Cassette{Target: rec.target.String(), Exchanges: append([]Exchange(nil), rec.exchanges...)}
+}
+
+func (rec *Recorder) Len() int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return len(rec.exchanges)
+}
+
+// Suite turns the recording into a single scenario, one step per exchange.
+// The target and proxy origins become ${baseVar}.
+func (rec *Recorder) Suite(name, baseVar string) *ir.TestSuite {
+	rec.mu.Lock()
+	origins := []string{strings.TrimSuffix(rec.target.String(), "/")}
+	for o := range rec.origins {
+		origins = append(origins, o)
+	}
+	rec.mu.Unlock()
+	sort.Slice(origins, func(i, j int) bool { return len(origins[i]) > len(origins[j]) })
+	return SuiteFrom(rec.Cassette(), name, baseVar, origins...)
+}
+
+// SuiteFrom converts exchanges to a suite; every string occurrence of the
+// given origins is replaced by ${baseVar}.
+func SuiteFrom(c *Cassette, name, baseVar string, origins ...string) *ir.TestSuite {
+	if baseVar == "" {
+		baseVar = "BASE_URL"
+	}
+	if name == "" {
+		name = "recorded"
+	}
+	param := func(s string) string {
+		for _, o := range origins {
+			if o != "" {
+				s = strings.ReplaceAll(s, o, "${"+baseVar+"}")
+			}
+		}
+		return s
+	}
+
+	sc := ir.Scenario{Name: "recorded session", Tags: []string{"recorded"}}
+	for i, ex := range c.Exchanges {
+		req := ir.Request{
+			Method: ex.Method,
+			URL:    "${" + baseVar + "}" + ex.Path,
+		}
+		for k, v := range ex.ReqHeaders {
+			if capture.KeepHeader(k) {
+				if req.Headers == nil {
+					req.Headers = map[string]string{}
+				}
+				if capture.Credential(k) {
+					v = capture.CredentialRef(k, v)
+				}
+				req.Headers[k] = param(v)
+			}
+		}
+		if ex.ReqBody != "" {
+			req.Body = paramBody(capture.ParseBody(ex.ReqBody), param)
+		}
+		sc.Steps = append(sc.Steps, ir.Step{
+			Name:    fmt.Sprintf("%d %s %s", i+1, ex.Method, ex.Path),
+			Request: req,
+			Expect:  Expectations(ex.Status, ex.RespBody, param),
+		})
+	}
+	return &ir.TestSuite{Name: name, Scenarios: []ir.Scenario{sc}}
+}
+
+// Expectations asserts the status and, for JSON object bodies, each
+// top-level scalar field that does not look volatile (UUIDs, timestamps).
+func Expectations(status int, body string, param func(string) string) []ir.Expectation {
+	out := []ir.Expectation{{Type: ir.ExpectStatus, Value: status}}
+	var obj map[string]any
+	if json.Unmarshal([]byte(body), &obj) != nil {
+		return out
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		switch v := obj[k].(type) {
+		case string:
+			if volatile(v) {
+				continue
+			}
+			out = append(out, ir.Expectation{Type: ir.ExpectJSONPath, Target: "$." + k, Value: param(v)})
+		case float64, bool:
+			out = append(out, ir.Expectation{Type: ir.ExpectJSONPath, Target: "$." + k, Value: v})
+		}
+	}
+	return out
+}
+
+var (
+	uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	dateRe = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}`)
+)
+
+func volatile(s string) bool {
+	return uuidRe.MatchString(s) || dateRe.MatchString(s)
+}
+
+func paramBody(v any, param func(string) string) any {
+	switch x := v.(type) {
+	case string:
+		return param(x)
+	case map[string]any:
+		for k, e := range x {
+			x[k] = paramBody(e, param)
+		}
+	case []any:
+		for i, e := range x {
+			x[i] = paramBody(e, param)
+		}
+	}
+	return v
+}
+
+// ---- Headers ----
+
+// maskCredential masks the value of a credential header, keeping an
+// Authorization scheme ("Bearer ***").
+func maskCredential(name, v string) string {
+	if !capture.Credential(name) {
+		return v
+	}
+	if scheme, _, ok := strings.Cut(v, " "); ok && strings.HasSuffix(http.CanonicalHeaderKey(name), "Authorization") {
+		return scheme + " " + redact.Mask
+	}
+	return redact.Mask
+}
+
+// ---- Cassette files ----
+
+func WriteCassette(path string, c *Cassette) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode cassette: %w", err)
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
+
+func LoadCassette(path string) (*Cassette, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read cassette: %w", err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("parse cassette: %w", err)
+	}
+	return &c, nil
+}
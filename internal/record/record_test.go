@@ -0,0 +1,165 @@
+package record_test
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sea-qa/internal/executor"
+	"sea-qa/internal/parser"
+	"sea-qa/internal/record"
+)
+
+func backend() *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/users":
+			b, _ := io.ReadAll(r.Body)
+			w.Header().Set("Location", srv.URL+"/users/7")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":7,"email":"` + extract(string(b)) + `","self":"` + srv.URL + `/users/7","createdAt":"2024-05-01T10:00:00Z"}`))
+		case r.URL.Path == "/users/7":
+			if r.Header.Get("Authorization") != "Bearer t0k3n" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Set-Cookie", "sid=s3ss10n")
+			_, _ = w.Write([]byte(`{"id":7,"active":true}`))
+		case r.URL.Path == "/gz" && strings.Contains(r.Header.Get("Accept-Encoding"), "gzip"):
+			w.Header().Set("Content-Encoding", "gzip")
+			zw := gzip.NewWriter(w)
+			_, _ = zw.Write([]byte(`{"zipped":true}`))
+			_ = zw.Close()
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return srv
+}
+
+func extract(body string) string {
+	_, rest, _ := strings.Cut(body, `"email":"`)
+	v, _, _ := strings.Cut(rest, `"`)
+	return v
+}
+
+func TestRecord_WritesRunnableSuiteAndReplays(t *testing.T) {
+	be := backend()
+	defer be.Close()
+
+	rec, err := record.New(be.URL)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	proxy := httptest.NewServer(rec)
+	defer proxy.Close()
+
+	req, _ := http.NewRequest("POST", proxy.URL+"/users", strings.NewReader(`{"email":"a@example.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Cookie", "session=secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 201 {
+		t.Fatalf("proxied status = %d", resp.StatusCode)
+	}
+	req, _ = http.NewRequest("GET", proxy.URL+"/users/7?expand=1", nil)
+	req.Header.Set("Authorization", "Bearer t0k3n")
+	if _, err := http.DefaultClient.Do(req); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	// An explicit Accept-Encoding must not leave compressed bytes in the recording.
+	req, _ = http.NewRequest("GET", proxy.URL+"/gz", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("get gz: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != `{"zipped":true}` {
+		t.Fatalf("proxied gzip body = %q", body)
+	}
+
+	suite := rec.Suite("rec", "BASE_URL")
+	data, err := parser.Marshal(suite)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	parsed, err := parser.New().ParseBytes(data)
+	if err != nil {
+		t.Fatalf("parse:\n%s\n%v", data, err)
+	}
+	steps := parsed.Scenarios[0].Steps
+	if len(steps) != 3 {
+		t.Fatalf("steps = %d", len(steps))
+	}
+	if steps[0].Request.URL != "${BASE_URL}/users" || steps[1].Request.URL != "${BASE_URL}/users/7?expand=1" {
+		t.Fatalf("urls: %q %q", steps[0].Request.URL, steps[1].Request.URL)
+	}
+	if _, ok := steps[0].Request.Headers["Cookie"]; ok {
+		t.Fatalf("cookie header kept: %v", steps[0].Request.Headers)
+	}
+	if got := steps[1].Request.Headers["Authorization"]; got != "Bearer ${TOKEN}" {
+		t.Fatalf("authorization = %q, want Bearer ${TOKEN}", got)
+	}
+	if len(steps[2].Expect) != 2 || steps[2].Expect[1].Target != "$.zipped" {
+		t.Fatalf("gzip expectations: %+v", steps[2].Expect)
+	}
+	if strings.Contains(string(data), "t0k3n") {
+		t.Fatalf("suite contains the token:\n%s", data)
+	}
+	var self, created bool
+	for _, e := range steps[0].Expect {
+		if e.Target == "$.self" && e.Value == "${BASE_URL}/users/7" {
+			self = true
+		}
+		if e.Target == "$.createdAt" {
+			created = true
+		}
+	}
+	if !self || created {
+		t.Fatalf("expectations: %+v", steps[0].Expect)
+	}
+
+	// the suite passes against the real backend...
+	res, err := executor.NewWithVars(map[string]string{"BASE_URL": be.URL, "TOKEN": "t0k3n"}).RunSuite(context.Background(), parsed)
+	if err != nil || !res.Passed {
+		t.Fatalf("run against backend: %v %+v", err, res)
+	}
+
+	// ...and against the replayed cassette
+	path := filepath.Join(t.TempDir(), "c.json")
+	if err := record.WriteCassette(path, rec.Cassette()); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := os.ReadFile(path)
+	for _, secret := range []string{"t0k3n", "s3ss10n"} {
+		if strings.Contains(string(raw), secret) {
+			t.Fatalf("cassette contains %q:\n%s", secret, raw)
+		}
+	}
+	c, err := record.LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stub := httptest.NewServer(record.NewReplayer(c))
+	defer stub.Close()
+	res, err = executor.NewWithVars(map[string]string{"BASE_URL": stub.URL, "TOKEN": "t0k3n"}).RunSuite(context.Background(), parsed)
+	if err != nil || !res.Passed {
+		t.Fatalf("run against replay: %v %+v", err, res)
+	}
+	if r, _ := http.Get(stub.URL + "/nope"); r.StatusCode != 404 {
+		t.Fatalf("unmatched replay status = %d", r.StatusCode)
+	}
+}
@@ -0,0 +1,100 @@
+package record
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"sea-qa/internal/capture"
+)
+
+// Replayer serves a cassette back. Requests match on method and path+query,
+// falling back to method and path; repeated requests walk through the
+// matching exchanges in recorded order and then keep returning the last one.
+// The recorded target origin is rewritten to the replayer's own.
+type Replayer struct {
+	c *Cassette
+
+	mu   sync.Mutex
+	hits map[string]int
+}
+
+func NewReplayer(c *Cassette) *Replayer {
+	return &Replayer{c: c, hits: map[string]int{}}
+}
+
+func (rp *Replayer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ex := rp.match(r.Method, r.URL.RequestURI(), r.URL.Path)
+	if ex == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"error": "no recorded exchange for " + r.Method + " " + r.URL.RequestURI(),
+		})
+		return
+	}
+	// Links to the recorded target point back at the stub instead.
+	target := strings.TrimSuffix(rp.c.Target, "/")
+	self := "http://" + r.Host
+	rewrite := func(s string) string {
+		if target == "" {
+			return s
+		}
+		return strings.ReplaceAll(s, target, self)
+	}
+	for k, vs := range ex.RespHeaders {
+		if capture.HopByHop(k) || http.CanonicalHeaderKey(k) == "Content-Length" {
+			continue
+		}
+		for _, v := range vs {
+			w.Header().Add(k, rewrite(v))
+		}
+	}
+	w.WriteHeader(ex.Status)
+	_, _ = w.Write([]byte(rewrite(ex.RespBody)))
+}
+
+func (rp *Replayer) match(method, uri, path string) *Exchange {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	var exact, loose []*Exchange
+	for i := range rp.c.Exchanges {
+		ex := &rp.c.Exchanges[i]
+		if ex.Method != method {
+			continue
+		}
+		if ex.Path == uri {
+			exact = append(exact, ex)
+		}
+		if pathOnly(ex.Path) == path {
+			loose = append(loose, ex)
+		}
+	}
+	key, list := method+" "+uri, exact
+	if len(list) == 0 {
+		key, list = method+" "+path+" (any query)", loose
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	n := rp.hits[key]
+	rp.hits[key] = n + 1
+	if n >= len(list) {
+		n = len(list) - 1
+	}
+	return list[n]
+}
+
+// Reset rewinds every sequence to its first exchange.
+func (rp *Replayer) Reset() {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.hits = map[string]int{}
+}
+
+func pathOnly(uri string) string {
+	p, _, _ := strings.Cut(uri, "?")
+	return p
+}
@@ -0,0 +1,330 @@
+// Package redact masks secrets in run results: values of secret vars,
+// values hooks declare secret, credential headers and configured JSON paths.
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"sea-qa/internal/ir"
+)
+
+// Mask replaces every redacted value.
+const Mask = "***"
+
+// minSecret is the shortest value masked by value; shorter ones would mask
+// unrelated text.
+const minSecret = 4
+
+// Built-in patterns, always active.
+var (
+	defaultHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "X-Auth-Token"}
+	defaultVars    = []string{"*TOKEN*", "*SECRET*", "*PASSWORD*", "*PASSWD*", "*API_KEY*", "*APIKEY*"}
+)
+
+// Redactor is safe for concurrent use.
+type Redactor struct {
+	headers []string
+	vars    []string
+	paths   [][]seg
+
+	mu       sync.Mutex
+	secrets  map[string]bool
+	replacer *strings.Replacer // rebuilt lazily when secrets change
+}
+
+// New merges the configs with the built-in patterns.
+func New(cfgs ...ir.Redaction) (*Redactor, error) {
+	r := &Redactor{
+		headers: lower(defaultHeaders),
+		vars:    lower(defaultVars),
+		secrets: map[string]bool{},
+	}
+	for _, c := range cfgs {
+		r.headers = append(r.headers, lower(c.Headers)...)
+		r.vars = append(r.vars, lower(c.Vars)...)
+		for _, p := range c.JSONPaths {
+			segs, err := parsePath(p)
+			if err != nil {
+				return nil, fmt.Errorf("redact json path %q: %w", p, err)
+			}
+			r.paths = append(r.paths, segs)
+		}
+	}
+	return r, nil
+}
+
+func lower(ss []string) []string {
+	out := make([]string, 0, len(ss))
+	for _, s := range ss {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, strings.ToLower(s))
+		}
+	}
+	return out
+}
+
+func matchAny(patterns []string, name string) bool {
+	name = strings.ToLower(name)
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SecretVar reports whether the var's value must be masked.
+func (r *Redactor) SecretVar(name string) bool { return matchAny(r.vars, name) }
+
+// SecretHeader reports whether the header's value must be masked.
+func (r *Redactor) SecretHeader(name string) bool { return matchAny(r.headers, name) }
+
+// AddSecret masks v wherever it appears from now on.
+func (r *Redactor) AddSecret(v string) {
+	if len(v) < minSecret {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.secrets[v] {
+		r.secrets[v] = true
+		r.replacer = nil
+	}
+}
+
+// AddVars registers the values of secret vars in vars.
+func (r *Redactor) AddVars(vars map[string]string) {
+	for k, v := range vars {
+		if r.SecretVar(k) {
+			r.AddSecret(v)
+		}
+	}
+}
+
+// String masks secret values in s.
+func (r *Redactor) String(s string) string {
+	if s == "" {
+		return s
+	}
+	r.mu.Lock()
+	if r.replacer == nil {
+		if len(r.secrets) == 0 {
+			r.mu.Unlock()
+			return s
+		}
+		vals := make([]string, 0, len(r.secrets))
+		for v := range r.secrets {
+			vals = append(vals, v)
+		}
+		// longest first, so a secret containing another is masked whole
+		sort.Slice(vals, func(i, j int) bool { return len(vals[i]) > len(vals[j]) })
+		pairs := make([]string, 0, 2*len(vals))
+		for _, v := range vals {
+			pairs = append(pairs, v, Mask)
+		}
+		r.replacer = strings.NewReplacer(pairs...)
+	}
+	rep := r.replacer
+	r.mu.Unlock()
+	return rep.Replace(s)
+}
+
+// Strings masks each element in place.
+func (r *Redactor) Strings(ss []string) {
+	for i := range ss {
+		ss[i] = r.String(ss[i])
+	}
+}
+
+// Headers returns a copy of h with secret headers and values masked.
+func (r *Redactor) Headers(h map[string]string) map[string]string {
+	if h == nil {
+		return nil
+	}
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		if r.SecretHeader(k) {
+			out[k] = Mask
+		} else {
+			out[k] = r.String(v)
+		}
+	}
+	return out
+}
+
+// HeaderValues is Headers for multi-valued headers.
+func (r *Redactor) HeaderValues(h map[string][]string) map[string][]string {
+	if h == nil {
+		return nil
+	}
+	out := make(map[string][]string, len(h))
+	for k, vs := range h {
+		cp := make([]string, len(vs))
+		for i, v := range vs {
+			if r.SecretHeader(k) {
+				cp[i] = Mask
+			} else {
+				cp[i] = r.String(v)
+			}
+		}
+		out[k] = cp
+	}
+	return out
+}
+
+// Body masks configured JSON paths (when body is JSON) and secret values.
+// The body is re-encoded only when a path matched.
+func (r *Redactor) Body(body string) string {
+	if len(r.paths) > 0 && body != "" {
+		dec := json.NewDecoder(strings.NewReader(body))
+		dec.UseNumber()
+		var doc any
+		if dec.Decode(&doc) == nil && !dec.More() {
+			changed := false
+			for _, p := range r.paths {
+				doc = apply(doc, p, &changed)
+			}
+			if changed {
+				var buf bytes.Buffer
+				enc := json.NewEncoder(&buf)
+				enc.SetEscapeHTML(false)
+				if enc.Encode(doc) == nil {
+					body = strings.TrimRight(buf.String(), "\n")
+				}
+			}
+		}
+	}
+	return r.String(body)
+}
+
+// RawJSON is Body for json.RawMessage.
+func (r *Redactor) RawJSON(b json.RawMessage) json.RawMessage {
+	if b == nil {
+		return nil
+	}
+	return json.RawMessage(r.Body(string(b)))
+}
+
+// Value masks secret values in the strings of a decoded JSON value.
+func (r *Redactor) Value(v any) any {
+	switch x := v.(type) {
+	case string:
+		return r.String(x)
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, e := range x {
+			out[k] = r.Value(e)
+		}
+		return out
+	case []any:
+		out := make([]any, len(x))
+		for i, e := range x {
+			out[i] = r.Value(e)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// ---- JSON paths ----
+
+// seg is one step of a path: a key (or * for any), an index (-1 for any),
+// optionally matched at any depth (..).
+type seg struct {
+	key   string
+	index int
+	isKey bool
+	deep  bool
+}
+
+// parsePath accepts $.a.b, $.a[*].b, $.a[0], $['a b'], $..a and $.*.
+func parsePath(p string) ([]seg, error) {
+	s := strings.TrimSpace(p)
+	if !strings.HasPrefix(s, "$") {
+		return nil, fmt.Errorf("must start with $")
+	}
+	s = s[1:]
+	var segs []seg
+	for s != "" {
+		deep := false
+		switch {
+		case strings.HasPrefix(s, ".."):
+			deep, s = true, s[2:]
+		case s[0] == '.':
+			s = s[1:]
+		case s[0] == '[':
+		default:
+			return nil, fmt.Errorf("unexpected %q", s)
+		}
+		if s != "" && s[0] == '[' {
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed [")
+			}
+			in := strings.TrimSpace(s[1:end])
+			s = s[end+1:]
+			switch {
+			case in == "*" || in == "":
+				segs = append(segs, seg{index: -1, deep: deep})
+			case len(in) >= 2 && (in[0] == '\'' || in[0] == '"') && in[len(in)-1] == in[0]:
+				segs = append(segs, seg{key: in[1 : len(in)-1], isKey: true, deep: deep})
+			default:
+				n, err := strconv.Atoi(in)
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("bad index %q", in)
+				}
+				segs = append(segs, seg{index: n, deep: deep})
+			}
+			continue
+		}
+		end := strings.IndexAny(s, ".[")
+		if end < 0 {
+			end = len(s)
+		}
+		if end == 0 {
+			return nil, fmt.Errorf("empty name")
+		}
+		segs = append(segs, seg{key: s[:end], isKey: true, deep: deep})
+		s = s[end:]
+	}
+	if len(segs) == 0 {
+		return nil, fmt.Errorf("matches the whole document")
+	}
+	return segs, nil
+}
+
+// apply returns v with the values matched by segs replaced by Mask.
+func apply(v any, segs []seg, changed *bool) any {
+	if len(segs) == 0 {
+		*changed = true
+		return Mask
+	}
+	s, rest := segs[0], segs[1:]
+	switch x := v.(type) {
+	case map[string]any:
+		for k, e := range x {
+			if s.isKey && (s.key == "*" || s.key == k) {
+				x[k] = apply(e, rest, changed)
+			} else if s.deep {
+				x[k] = apply(e, segs, changed)
+			}
+		}
+	case []any:
+		for i, e := range x {
+			if !s.isKey && (s.index < 0 || s.index == i) {
+				x[i] = apply(e, rest, changed)
+			} else if s.deep {
+				x[i] = apply(e, segs, changed)
+			}
+		}
+	}
+	return v
+}
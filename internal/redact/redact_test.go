@@ -0,0 +1,54 @@
+package redact_test
+
+import (
+	"testing"
+
+	"sea-qa/internal/ir"
+	"sea-qa/internal/redact"
+)
+
+func TestSecretsAndHeaders(t *testing.T) {
+	r, err := redact.New(ir.Redaction{Vars: []string{"DB_*"}, Headers: []string{"X-Session-*"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.AddVars(map[string]string{"API_TOKEN": "tok-123", "DB_PASS": "hunter22", "BASE_URL": "http://api", "MY_SECRET": "abc"})
+	r.AddSecret("tok-123-refresh")
+
+	if got := r.String("a=tok-123-refresh b=tok-123 c=hunter22 d=http://api e=abc"); got != "a=*** b=*** c=*** d=http://api e=abc" {
+		t.Errorf("String = %q", got)
+	}
+	h := r.Headers(map[string]string{"authorization": "Bearer x", "X-Session-Id": "s1", "Accept": "tok-123"})
+	if h["authorization"] != "***" || h["X-Session-Id"] != "***" || h["Accept"] != "***" {
+		t.Errorf("Headers = %v", h)
+	}
+	hv := r.HeaderValues(map[string][]string{"Set-Cookie": {"a=1", "b=2"}, "Etag": {"1"}})
+	if hv["Set-Cookie"][0] != "***" || hv["Set-Cookie"][1] != "***" || hv["Etag"][0] != "1" {
+		t.Errorf("HeaderValues = %v", hv)
+	}
+}
+
+func TestBodyJSONPaths(t *testing.T) {
+	r, err := redact.New(ir.Redaction{JSONPaths: []string{"$.user.password", "$..token", "$.cards[*].number", "$['api key']"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := `{"user":{"name":"ann","password":"p"},"session":{"token":"t1","nested":[{"token":42}]},"cards":[{"number":"4111","exp":"12/30"}],"api key":"k","n":1.50}`
+	want := `{"api key":"***","cards":[{"exp":"12/30","number":"***"}],"n":1.50,"session":{"nested":[{"token":"***"}],"token":"***"},"user":{"name":"ann","password":"***"}}`
+	if got := r.Body(in); got != want {
+		t.Errorf("Body =\n%s\nwant\n%s", got, want)
+	}
+
+	// Untouched and non-JSON bodies keep their formatting.
+	for _, b := range []string{"{\n  \"ok\": true\n}", "token=abc"} {
+		if got := r.Body(b); got != b {
+			t.Errorf("Body(%q) = %q", b, got)
+		}
+	}
+
+	for _, p := range []string{"user", "$", "$.a[", "$.a[x]", "$.a..", "$..['x'"} {
+		if _, err := redact.New(ir.Redaction{JSONPaths: []string{p}}); err == nil {
+			t.Errorf("path %q: expected error", p)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package reporter
+
+import (
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sea-qa/internal/executor"
+	"sea-qa/internal/hooks"
+)
+
+// writeChecksHTML renders the checks, metrics and attachments hooks
+// reported for a step.
+func writeChecksHTML(sb *strings.Builder, st executor.StepResult) {
+	if len(st.Checks) > 0 {
+		sb.WriteString(`<div class="small muted" style="margin-top:10px;">Checks</div>`)
+		sb.WriteString(`<table class="checks"><tr><th></th><th>Check</th><th>Expected</th><th>Actual</th><th>Message</th></tr>`)
+		for _, c := range st.Checks {
+			sb.WriteString(`<tr><td>` + badgeStatus(c.Passed) + `</td><td>` + html.EscapeString(c.Name) + `</td>`)
+			sb.WriteString(`<td><code>` + html.EscapeString(optional(c.Expected)) + `</code></td>`)
+			sb.WriteString(`<td><code>` + html.EscapeString(optional(c.Actual)) + `</code></td>`)
+			sb.WriteString(`<td>` + html.EscapeString(c.Message) + `</td></tr>`)
+		}
+		sb.WriteString(`</table>`)
+	}
+
+	if len(st.Metrics) > 0 {
+		keys := make([]string, 0, len(st.Metrics))
+		for k := range st.Metrics {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		sb.WriteString(`<div class="small muted" style="margin-top:10px;">Metrics</div><div>`)
+		for _, k := range keys {
+			sb.WriteString(chip(html.EscapeString(k)+": "+strconv.FormatFloat(st.Metrics[k], 'g', -1, 64)) + ` `)
+		}
+		sb.WriteString(`</div>`)
+	}
+
+	for _, a := range st.Attachments {
+		label := a.Name
+		if a.ContentType != "" {
+			label += " (" + a.ContentType + ")"
+		}
+		sb.WriteString(`<details class="attachment"><summary class="small muted">Attachment: ` + html.EscapeString(label) + `</summary>`)
+		switch {
+		case a.JSON != nil:
+			sb.WriteString(`<pre>` + html.EscapeString(prettyJSON(string(a.JSON))) + `</pre>`)
+		case a.Text != "":
+			sb.WriteString(`<pre>` + html.EscapeString(a.Text) + `</pre>`)
+		}
+		if a.Path != "" {
+			sb.WriteString(`<div class="small muted"><code>` + html.EscapeString(a.Path) + `</code></div>`)
+		}
+		sb.WriteString(`</details>`)
+	}
+}
+
+// writeHooksHTML renders one row per hook invocation, with its captured
+// stderr and decoded output collapsed below the table.
+func writeHooksHTML(sb *strings.Builder, hrs []hooks.Result) {
+	if len(hrs) == 0 {
+		return
+	}
+	sb.WriteString(`<div class="small muted" style="margin-top:10px;">Hooks</div>`)
+	sb.WriteString(`<table class="checks"><tr><th></th><th>When</th><th>Type</th><th>Hook</th><th>Duration</th><th>Exit / status</th><th>Error</th></tr>`)
+	for _, hr := range hrs {
+		result := ""
+		switch {
+		case hr.ExitCode != nil:
+			result = "exit " + strconv.Itoa(*hr.ExitCode)
+		case hr.Status != 0:
+			result = "HTTP " + strconv.Itoa(hr.Status)
+			if hr.Attempts > 1 {
+				result += " (" + strconv.Itoa(hr.Attempts) + " attempts)"
+			}
+		}
+		sb.WriteString(`<tr><td>` + badgeStatus(hr.Error == "" && (hr.Output == nil || len(hr.Output.Errors) == 0)) + `</td>`)
+		sb.WriteString(`<td>` + html.EscapeString(hr.When) + `</td><td>` + html.EscapeString(tern(hr.Type == "", "process", hr.Type)) + `</td>`)
+		sb.WriteString(`<td><code>` + html.EscapeString(hr.Name) + `</code></td><td>` + ms(hr.DurationMs) + `</td>`)
+		sb.WriteString(`<td>` + html.EscapeString(result) + `</td><td>` + html.EscapeString(hr.Error) + `</td></tr>`)
+	}
+	sb.WriteString(`</table>`)
+	for _, hr := range hrs {
+		if hr.Stderr != "" {
+			sb.WriteString(`<details class="attachment"><summary class="small muted">stderr: ` + html.EscapeString(hr.When+" "+hr.Name) + `</summary>`)
+			sb.WriteString(`<pre>` + html.EscapeString(hr.Stderr) + `</pre></details>`)
+		}
+		if hr.Output != nil && (len(hr.Output.Vars) > 0 || hr.Output.Request != nil || len(hr.Output.Errors) > 0) {
+			sb.WriteString(`<details class="attachment"><summary class="small muted">output: ` + html.EscapeString(hr.When+" "+hr.Name) + `</summary>`)
+			sb.WriteString(`<pre>` + html.EscapeString(prettyJSON(compact(hr.Output))) + `</pre></details>`)
+		}
+	}
+}
+
+func optional(v any) string {
+	if v == nil {
+		return ""
+	}
+	return compact(v)
+}
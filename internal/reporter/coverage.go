@@ -133,3 +133,31 @@ func pct(n, d int) float64 {
 	}
 	return float64(n) * 100.0 / float64(d)
 }
+
+// MultiCoverageReport is written instead of CoverageReport when a suite
+// routes requests across several specs; totals aggregate every spec.
+type MultiCoverageReport struct {
+	Total   int                       `json:"total"`
+	Covered int                       `json:"covered"`
+	Percent float64                   `json:"percent"`
+	Specs   map[string]CoverageReport `json:"specs"`
+}
+
+// docs and covered are keyed by spec name; covered is spec -> method -> pathTemplate -> true
+func ComputeMultiCoverage(docs map[string]*openapi3.T, covered map[string]map[string]map[string]bool) MultiCoverageReport {
+	rep := MultiCoverageReport{Specs: map[string]CoverageReport{}}
+	for name, doc := range docs {
+		cr := ComputeCoverage(doc, covered[name])
+		rep.Specs[name] = cr
+		rep.Total += cr.Total
+		rep.Covered += cr.Covered
+	}
+	rep.Percent = pct(rep.Covered, rep.Total)
+	return rep
+}
+
+func WriteMultiCoverage(w io.Writer, docs map[string]*openapi3.T, covered map[string]map[string]map[string]bool) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ComputeMultiCoverage(docs, covered))
+}
@@ -50,3 +50,45 @@ paths:
 		t.Fatalf("percent=%v", rep.Percent)
 	}
 }
+
+func TestComputeMultiCoverage(t *testing.T) {
+	load := func(spec string) *openapi3.T {
+		doc, err := (&openapi3.Loader{}).LoadFromData([]byte(spec))
+		if err != nil {
+			t.Fatalf("load: %v", err)
+		}
+		return doc
+	}
+	docs := map[string]*openapi3.T{
+		"users": load(`
+openapi: 3.0.3
+info: {title: users, version: "1"}
+paths:
+  /users:
+    get:  { responses: { "200": { description: ok } } }
+    post: { responses: { "201": { description: ok } } }
+`),
+		"orders": load(`
+openapi: 3.0.3
+info: {title: orders, version: "1"}
+paths:
+  /orders:
+    get: { responses: { "200": { description: ok } } }
+`),
+	}
+	covered := map[string]map[string]map[string]bool{
+		"users":  {"GET": {"/users": true}},
+		"orders": {"GET": {"/orders": true}},
+	}
+
+	rep := reporter.ComputeMultiCoverage(docs, covered)
+	if rep.Total != 3 || rep.Covered != 2 {
+		t.Fatalf("aggregate = %d/%d, want 2/3", rep.Covered, rep.Total)
+	}
+	if got := rep.Specs["users"]; got.Total != 2 || got.Covered != 1 {
+		t.Fatalf("users = %+v", got)
+	}
+	if got := rep.Specs["orders"]; got.Percent != 100 {
+		t.Fatalf("orders = %+v", got)
+	}
+}
@@ -0,0 +1,121 @@
+package reporter
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+
+	"sea-qa/internal/contract"
+)
+
+// -------- Contract diff (Markdown) --------
+
+// WriteDiffMarkdown renders a diff report for pasting into pull request reviews.
+func WriteDiffMarkdown(w io.Writer, title string, rep contract.DiffReport) error {
+	var sb strings.Builder
+	sb.WriteString("## " + title + "\n\n")
+	if len(rep.Changes) == 0 {
+		sb.WriteString("No changes.\n")
+	} else {
+		fmt.Fprintf(&sb, "**%d change(s), %d breaking**\n\n", len(rep.Changes), rep.Breaking)
+		writeMDTable(&sb, "Breaking", filterChanges(rep.Changes, true))
+		writeMDTable(&sb, "Non-breaking", filterChanges(rep.Changes, false))
+	}
+	if len(rep.Ignored) > 0 {
+		sb.WriteString("<details><summary>Ignored (" + strconv.Itoa(len(rep.Ignored)) + ")</summary>\n\n")
+		writeMDTable(&sb, "", rep.Ignored)
+		sb.WriteString("</details>\n")
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeMDTable(sb *strings.Builder, heading string, changes []contract.Change) {
+	if len(changes) == 0 {
+		return
+	}
+	if heading != "" {
+		sb.WriteString("### " + heading + "\n\n")
+	}
+	sb.WriteString("| Operation | Change | Details | Pointer |\n")
+	sb.WriteString("|---|---|---|---|\n")
+	for _, c := range changes {
+		fmt.Fprintf(sb, "| `%s %s` | `%s` | %s | `%s` |\n",
+			c.Method, mdEscape(c.Path), c.ID, mdEscape(c.Message), mdEscape(c.Pointer))
+	}
+	sb.WriteString("\n")
+}
+
+func mdEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+// -------- Contract diff (HTML) --------
+
+func WriteDiffHTML(w io.Writer, title string, rep contract.DiffReport) error {
+	var sb strings.Builder
+
+	sb.WriteString(`<!doctype html><html lang="en"><head><meta charset="utf-8">`)
+	sb.WriteString(`<meta name="viewport" content="width=device-width,initial-scale=1">`)
+	sb.WriteString(`<title>sea-qa Contract Diff — ` + html.EscapeString(title) + `</title>`)
+	sb.WriteString(`<style>
+:root { --ok:#0a0; --bad:#b00; --muted:#666; --chip:#eee; --line:#e5e5e5; }
+body{font-family:system-ui,Segoe UI,Roboto,Arial,sans-serif;margin:24px;line-height:1.45}
+h1{margin:0 0 12px}
+h2{margin:18px 0 8px;font-size:1.05rem}
+.summary{display:flex;gap:12px;align-items:center;margin:12px 0 18px}
+.pass{color:var(--ok)} .fail{color:var(--bad)}
+.badge{display:inline-block;padding:2px 8px;border-radius:999px;background:var(--chip);font-size:.85rem}
+table{border-collapse:collapse;width:100%}
+th,td{border-bottom:1px solid var(--line);padding:6px 8px;text-align:left;vertical-align:top}
+code{font-size:.85rem}
+.muted{color:var(--muted)}
+</style></head><body>`)
+
+	sb.WriteString(`<h1>` + html.EscapeString(title) + `</h1>`)
+	sb.WriteString(`<div class="summary">`)
+	sb.WriteString(`<div>Breaking: <strong class="` + statusClass(rep.Breaking == 0) + `">` + strconv.Itoa(rep.Breaking) + `</strong></div>`)
+	sb.WriteString(chip("Changes: " + strconv.Itoa(len(rep.Changes))))
+	if len(rep.Ignored) > 0 {
+		sb.WriteString(chip("Ignored: " + strconv.Itoa(len(rep.Ignored))))
+	}
+	sb.WriteString(`</div>`)
+
+	if len(rep.Changes) == 0 {
+		sb.WriteString(`<div class="muted">No changes.</div>`)
+	}
+	writeHTMLTable(&sb, "Breaking", filterChanges(rep.Changes, true))
+	writeHTMLTable(&sb, "Non-breaking", filterChanges(rep.Changes, false))
+	writeHTMLTable(&sb, "Ignored", rep.Ignored)
+
+	sb.WriteString(`</body></html>`)
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeHTMLTable(sb *strings.Builder, heading string, changes []contract.Change) {
+	if len(changes) == 0 {
+		return
+	}
+	sb.WriteString(`<h2>` + html.EscapeString(heading) + `</h2><table>`)
+	sb.WriteString(`<tr><th>Operation</th><th>Change</th><th>Details</th><th>Pointer</th></tr>`)
+	for _, c := range changes {
+		sb.WriteString(`<tr><td><code>` + html.EscapeString(c.Method+" "+c.Path) + `</code></td>`)
+		sb.WriteString(`<td><code class="` + statusClass(!c.Breaking) + `">` + html.EscapeString(c.ID) + `</code></td>`)
+		sb.WriteString(`<td>` + html.EscapeString(c.Message) + `</td>`)
+		sb.WriteString(`<td><code class="muted">` + html.EscapeString(c.Pointer) + `</code></td></tr>`)
+	}
+	sb.WriteString(`</table>`)
+}
+
+func filterChanges(in []contract.Change, breaking bool) []contract.Change {
+	var out []contract.Change
+	for _, c := range in {
+		if c.Breaking == breaking {
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,45 @@
+package reporter_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"sea-qa/internal/contract"
+	"sea-qa/internal/reporter"
+)
+
+func TestWriteDiffMarkdownAndHTML(t *testing.T) {
+	rep := contract.DiffReport{
+		Changes: []contract.Change{
+			{ID: contract.ChangeRespPropertyRemoved, Breaking: true, Method: "GET", Path: "/users/{id}",
+				Pointer: "/paths/~1users~1{id}/get/responses/200/content/application~1json/schema/properties/email",
+				Message: `response property "email" removed`},
+			{ID: contract.ChangeOperationAdded, Method: "GET", Path: "/status", Pointer: "/paths/~1status/get", Message: "operation added"},
+		},
+		Ignored:  []contract.Change{{ID: contract.ChangeStatusRemoved, Breaking: true, Method: "POST", Path: "/a|b"}},
+		Breaking: 1,
+	}
+
+	var md bytes.Buffer
+	if err := reporter.WriteDiffMarkdown(&md, "Contract diff", rep); err != nil {
+		t.Fatalf("WriteDiffMarkdown: %v", err)
+	}
+	out := md.String()
+	for _, want := range []string{"**2 change(s), 1 breaking**", "### Breaking", "### Non-breaking", "`response-property-removed`", `/a\|b`, "Ignored (1)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q:\n%s", want, out)
+		}
+	}
+
+	var h bytes.Buffer
+	if err := reporter.WriteDiffHTML(&h, "Contract <diff>", rep); err != nil {
+		t.Fatalf("WriteDiffHTML: %v", err)
+	}
+	if strings.Contains(h.String(), "<diff>") {
+		t.Fatalf("title not escaped")
+	}
+	if !strings.Contains(h.String(), "response property &#34;email&#34; removed") {
+		t.Fatalf("html missing change message:\n%s", h.String())
+	}
+}
@@ -0,0 +1,61 @@
+package reporter
+
+import (
+	"encoding/json"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+
+	"sea-qa/internal/contract"
+)
+
+type DriftReport struct {
+	Operations int                       `json:"operations"`
+	Items      int                       `json:"items"`
+	Drift      []contract.OperationDrift `json:"drift"`
+}
+
+func NewDriftReport(drift []contract.OperationDrift) DriftReport {
+	rep := DriftReport{Operations: len(drift), Drift: drift}
+	if rep.Drift == nil {
+		rep.Drift = []contract.OperationDrift{}
+	}
+	for _, op := range drift {
+		rep.Items += len(op.Items)
+	}
+	return rep
+}
+
+func WriteDrift(w io.Writer, drift []contract.OperationDrift) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(NewDriftReport(drift))
+}
+
+// writeDriftHTML renders the drift card of the run report.
+func writeDriftHTML(sb *strings.Builder, drift []contract.OperationDrift) {
+	if len(drift) == 0 {
+		return
+	}
+	rep := NewDriftReport(drift)
+	sb.WriteString(`<div class="card"><h2>Spec drift — ` + chip(strconv.Itoa(rep.Items)+" undocumented") + ` ` + chip(strconv.Itoa(rep.Operations)+" operation(s)") + `</h2>`)
+	sb.WriteString(`<div class="small muted">On the wire but not in the spec. Validation accepts these, so document or remove them before clients depend on them.</div>`)
+	sb.WriteString(`<table class="drift"><tr><th>Operation</th><th>Kind</th><th>Status</th><th>Name</th><th>Seen</th></tr>`)
+	for _, op := range drift {
+		name := op.Method + " " + op.Path
+		if op.Spec != "" {
+			name = "[" + op.Spec + "] " + name
+		}
+		for i, it := range op.Items {
+			sb.WriteString(`<tr>`)
+			if i == 0 {
+				sb.WriteString(`<td rowspan="` + strconv.Itoa(len(op.Items)) + `"><code>` + html.EscapeString(name) + `</code></td>`)
+			}
+			sb.WriteString(`<td>` + html.EscapeString(it.Kind) + `</td><td>` + html.EscapeString(it.Status) + `</td>`)
+			sb.WriteString(`<td><code>` + html.EscapeString(it.Name) + `</code></td>`)
+			sb.WriteString(`<td>` + strconv.Itoa(it.Count) + `/` + strconv.Itoa(op.Responses) + `</td></tr>`)
+		}
+	}
+	sb.WriteString(`</table></div>`)
+}
@@ -11,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 
+	"sea-qa/internal/curl"
 	"sea-qa/internal/executor"
 )
 
@@ -40,6 +41,8 @@ pre{background:#f8f8f8;padding:12px;border-radius:8px;overflow:auto;max-height:3
 hr{border:0;border-top:1px solid var(--line);margin:20px 0}
 .small{font-size:.85rem}
 .kv{margin-top:6px}
+table.drift,table.checks{border-collapse:collapse;width:100%;margin-top:8px}
+.drift th,.drift td,.checks th,.checks td{border-bottom:1px solid var(--line);padding:4px 8px;text-align:left;vertical-align:top}
 </style></head><body>`)
 
 	// Header
@@ -48,12 +51,29 @@ hr{border:0;border-top:1px solid var(--line);margin:20px 0}
 	sb.WriteString(`<div>Status: <strong class="` + statusClass(res.Passed) + `">` + tern(res.Passed, "PASS", "FAIL") + `</strong></div>`)
 	sb.WriteString(chip("Duration: " + ms(res.DurationMs)))
 	sb.WriteString(chip("Scenarios: " + strconv.Itoa(len(res.Scenarios))))
+	if len(res.Drift) > 0 {
+		sb.WriteString(chip("Drift: " + strconv.Itoa(NewDriftReport(res.Drift).Items)))
+	}
 	sb.WriteString(`</div><hr>`)
 
+	if len(res.Errors) > 0 || len(res.HookResults) > 0 {
+		sb.WriteString(`<div class="card"><h2>Suite hooks — ` + badgeStatus(len(res.Errors) == 0) + `</h2>`)
+		if len(res.Errors) > 0 {
+			sb.WriteString(errorsPre(res.Errors))
+		}
+		writeHooksHTML(&sb, res.HookResults)
+		sb.WriteString(`</div>`)
+	}
+	writeDriftHTML(&sb, res.Drift)
+
 	// Scenarios
 	for _, sc := range res.Scenarios {
 		sb.WriteString(`<div class="card">`)
 		sb.WriteString(`<h2>` + html.EscapeString(sc.Name) + ` — ` + badgeStatus(sc.Passed) + ` ` + chip(ms(sc.DurationMs)) + `</h2>`)
+		if len(sc.Errors) > 0 {
+			sb.WriteString(`<div class="small muted">Scenario hooks</div>` + errorsPre(sc.Errors))
+		}
+		writeHooksHTML(&sb, sc.HookResults)
 
 		for i, st := range sc.Steps {
 			sb.WriteString(`<div class="step">`)
@@ -62,14 +82,12 @@ hr{border:0;border-top:1px solid var(--line);margin:20px 0}
 
 			// Errors
 			if len(st.Errors) > 0 {
-				sb.WriteString(`<pre>`)
-				for _, e := range st.Errors {
-					sb.WriteString(html.EscapeString(e) + "\n")
-				}
-				sb.WriteString(`</pre>`)
-			} else {
+				sb.WriteString(errorsPre(st.Errors))
+			} else if !anyFailed(st.Checks) {
 				sb.WriteString(`<div class="small muted">No errors.</div>`)
 			}
+			writeChecksHTML(&sb, st)
+			writeHooksHTML(&sb, st.HookResults)
 
 			// Request
 			sb.WriteString(`<div class="small muted" style="margin-top:10px;">Request</div>`)
@@ -82,6 +100,10 @@ hr{border:0;border-top:1px solid var(--line);margin:20px 0}
 			if st.ReqBody != "" {
 				sb.WriteString(`<pre>` + html.EscapeString(prettyJSON(st.ReqBody)) + `</pre>`)
 			}
+			if st.Method != "" && st.URL != "" {
+				sb.WriteString(`<div class="small muted" style="margin-top:10px;">curl</div>`)
+				sb.WriteString(`<pre class="curl">` + html.EscapeString(curl.Command(st.Method, st.URL, st.ReqHeaders, st.ReqBody)) + `</pre>`)
+			}
 
 			// Response
 			sb.WriteString(`<div class="small muted" style="margin-top:10px;">Response</div>`)
@@ -187,3 +209,13 @@ func prettyJSON(s string) string {
 	}
 	return s
 }
+
+func errorsPre(errs []string) string {
+	var sb strings.Builder
+	sb.WriteString(`<pre>`)
+	for _, e := range errs {
+		sb.WriteString(html.EscapeString(e) + "\n")
+	}
+	sb.WriteString(`</pre>`)
+	return sb.String()
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"sea-qa/internal/executor"
+	"sea-qa/internal/hooks"
 	"sea-qa/internal/reporter"
 )
 
@@ -57,6 +58,78 @@ func TestWriteJUnit_Basic(t *testing.T) {
 	}
 }
 
+func TestWriteJUnit_HookFailures(t *testing.T) {
+	res := &executor.SuiteResult{
+		Errors: []string{"hook(beforeSuite) error: exit 1"},
+		Scenarios: []executor.ScenarioResult{{
+			Name:   "Scenario A",
+			Errors: []string{"seed failed"},
+			Steps:  []executor.StepResult{{Passed: true, StatusCode: 200}},
+		}},
+	}
+	var buf bytes.Buffer
+	if err := reporter.WriteJUnit(&buf, "Users API", res); err != nil {
+		t.Fatalf("WriteJUnit error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`tests="3"`, `failures="2"`,
+		`<testcase classname="Users API" name="suite-hooks"`,
+		`<testcase classname="Scenario A" name="scenario-hooks"`,
+		`message="seed failed" type="HookError"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %s in:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteJUnit_HookChecks(t *testing.T) {
+	exit := 0
+	res := &executor.SuiteResult{
+		Scenarios: []executor.ScenarioResult{{
+			Name: "Scenario A",
+			Steps: []executor.StepResult{{
+				Passed: false,
+				Checks: []hooks.Assertion{
+					{Name: "audit row", Passed: false, Expected: 1, Actual: 0},
+					{Name: "cache warm", Passed: true},
+				},
+				Attachments: []hooks.Attachment{{Name: "log", Text: "GET /users", Path: "out/log.txt"}},
+				Metrics:     map[string]float64{"db_ms": 12.5},
+				HookResults: []hooks.Result{{When: "after", Type: "process", Name: "./audit.sh", DurationMs: 7, ExitCode: &exit, Stderr: "checking <audit>"}},
+			}},
+		}},
+	}
+	var buf bytes.Buffer
+	if err := reporter.WriteJUnit(&buf, "Users API", res); err != nil {
+		t.Fatalf("WriteJUnit error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`tests="3"`, `failures="1"`,
+		`name="step-1: audit row"`,
+		`<failure message="expected 1, actual 0" type="AssertionError">`,
+		`name="step-1: cache warm"`,
+		`<property name="metric.db_ms" value="12.5"></property>`,
+		`--- log ---&#xA;GET /users&#xA;[[ATTACHMENT|out/log.txt]]`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %s in:\n%s", want, out)
+		}
+	}
+
+	var html bytes.Buffer
+	if err := reporter.WriteHTML(&html, "Users API", res); err != nil {
+		t.Fatalf("WriteHTML error: %v", err)
+	}
+	for _, want := range []string{`<table class="checks">`, `audit row`, `db_ms: 12.5`, `Attachment: log`, `<code>./audit.sh</code>`, `exit 0`, `checking &lt;audit&gt;`} {
+		if !strings.Contains(html.String(), want) {
+			t.Errorf("html missing %s", want)
+		}
+	}
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
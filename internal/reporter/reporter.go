@@ -5,8 +5,12 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"sort"
+	"strconv"
+	"strings"
 
 	"sea-qa/internal/executor"
+	"sea-qa/internal/hooks"
 )
 
 // -------- JSON --------
@@ -30,10 +34,21 @@ type junitTestsuite struct {
 }
 
 type junitTestcase struct {
-	Classname string        `xml:"classname,attr"`
-	Name      string        `xml:"name,attr"`
-	Time      string        `xml:"time,attr"`
-	Failure   *junitFailure `xml:"failure,omitempty"`
+	Classname  string           `xml:"classname,attr"`
+	Name       string           `xml:"name,attr"`
+	Time       string           `xml:"time,attr"`
+	Properties *junitProperties `xml:"properties,omitempty"`
+	Failure    *junitFailure    `xml:"failure,omitempty"`
+	SystemOut  string           `xml:"system-out,omitempty"`
+}
+
+type junitProperties struct {
+	Property []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
 }
 
 type junitFailure struct {
@@ -46,7 +61,24 @@ func WriteJUnit(w io.Writer, suiteName string, res *executor.SuiteResult) error
 	var total, failures int
 	var cases []junitTestcase
 
+	hookCase := func(class, name string, errs []string) {
+		total++
+		failures++
+		cases = append(cases, junitTestcase{
+			Classname: class,
+			Name:      name,
+			Time:      "0.000",
+			Failure:   &junitFailure{Message: errs[0], Type: "HookError", Text: joinErrs(errs)},
+		})
+	}
+	if len(res.Errors) > 0 {
+		hookCase(suiteName, "suite-hooks", res.Errors)
+	}
+
 	for _, sc := range res.Scenarios {
+		if len(sc.Errors) > 0 {
+			hookCase(sc.Name, "scenario-hooks", sc.Errors)
+		}
 		for i, st := range sc.Steps {
 			total++
 			tc := junitTestcase{
@@ -54,7 +86,9 @@ func WriteJUnit(w io.Writer, suiteName string, res *executor.SuiteResult) error
 				Name:      fmt.Sprintf("step-%d", i+1),
 				Time:      fmt.Sprintf("%.3f", st.DurationMs/1000.0),
 			}
-			if !st.Passed {
+			// A step that failed only through hook checks is reported by
+			// the check cases below.
+			if !st.Passed && (len(st.Errors) > 0 || !anyFailed(st.Checks)) {
 				failures++
 				msg := "assertion failed"
 				if len(st.Errors) > 0 {
@@ -66,7 +100,23 @@ func WriteJUnit(w io.Writer, suiteName string, res *executor.SuiteResult) error
 					Text:    joinErrs(st.Errors),
 				}
 			}
+			tc.Properties = metricProperties(st.Metrics)
+			tc.SystemOut = attachmentText(st.Attachments)
 			cases = append(cases, tc)
+
+			for _, c := range st.Checks {
+				total++
+				cc := junitTestcase{
+					Classname: sc.Name,
+					Name:      fmt.Sprintf("step-%d: %s", i+1, c.Name),
+					Time:      "0.000",
+				}
+				if !c.Passed {
+					failures++
+					cc.Failure = &junitFailure{Message: CheckMessage(c), Type: "AssertionError", Text: CheckMessage(c)}
+				}
+				cases = append(cases, cc)
+			}
 		}
 	}
 
@@ -83,6 +133,83 @@ func WriteJUnit(w io.Writer, suiteName string, res *executor.SuiteResult) error
 	return enc.Encode(ts)
 }
 
+func anyFailed(checks []hooks.Assertion) bool {
+	for _, c := range checks {
+		if !c.Passed {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckMessage describes a failed check: its message, or expected vs actual.
+func CheckMessage(c hooks.Assertion) string {
+	msg := c.Message
+	if c.Expected != nil || c.Actual != nil {
+		ea := fmt.Sprintf("expected %s, actual %s", compact(c.Expected), compact(c.Actual))
+		if msg == "" {
+			msg = ea
+		} else {
+			msg += " (" + ea + ")"
+		}
+	}
+	if msg == "" {
+		msg = "check failed"
+	}
+	return msg
+}
+
+func compact(v any) string {
+	if v == nil {
+		return "null"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func metricProperties(m map[string]float64) *junitProperties {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	p := &junitProperties{}
+	for _, k := range keys {
+		p.Property = append(p.Property, junitProperty{Name: "metric." + k, Value: strconv.FormatFloat(m[k], 'g', -1, 64)})
+	}
+	return p
+}
+
+// attachmentText renders attachments for <system-out>. File attachments
+// also get the [[ATTACHMENT|path]] marker CI plugins pick up.
+func attachmentText(as []hooks.Attachment) string {
+	var sb strings.Builder
+	for _, a := range as {
+		fmt.Fprintf(&sb, "--- %s", a.Name)
+		if a.ContentType != "" {
+			fmt.Fprintf(&sb, " (%s)", a.ContentType)
+		}
+		sb.WriteString(" ---\n")
+		switch {
+		case a.JSON != nil:
+			sb.Write(a.JSON)
+			sb.WriteString("\n")
+		case a.Text != "":
+			sb.WriteString(strings.TrimRight(a.Text, "\n") + "\n")
+		}
+		if a.Path != "" {
+			fmt.Fprintf(&sb, "[[ATTACHMENT|%s]]\n", a.Path)
+		}
+	}
+	return sb.String()
+}
+
 func joinErrs(errs []string) string {
 	if len(errs) == 0 {
 		return ""
@@ -0,0 +1,204 @@
+package sample
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Direction decides which of readOnly/writeOnly properties are skipped.
+type Direction int
+
+const (
+	Request  Direction = iota // skips readOnly properties
+	Response                  // skips writeOnly properties
+)
+
+const maxDepth = 8
+
+// FromMediaType prefers the media type's own example(s) and falls back to
+// the schema.
+func FromMediaType(mt *openapi3.MediaType, dir Direction) any {
+	if mt == nil {
+		return nil
+	}
+	if mt.Example != nil {
+		return mt.Example
+	}
+	if len(mt.Examples) > 0 {
+		names := make([]string, 0, len(mt.Examples))
+		for n := range mt.Examples {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			if ex := mt.Examples[n]; ex != nil && ex.Value != nil && ex.Value.Value != nil {
+				return ex.Value.Value
+			}
+		}
+	}
+	if mt.Schema == nil {
+		return nil
+	}
+	return FromSchema(mt.Schema.Value, dir)
+}
+
+// FromSchema returns, in order of preference: the schema example, its
+// default, the first enum value, or a placeholder derived from type, format
+// and constraints.
+func FromSchema(s *openapi3.Schema, dir Direction) any {
+	return fromSchema(s, dir, 0)
+}
+
+func fromSchema(s *openapi3.Schema, dir Direction, depth int) any {
+	if s == nil || depth > maxDepth {
+		return nil
+	}
+	if s.Example != nil {
+		return s.Example
+	}
+	if s.Default != nil {
+		return s.Default
+	}
+	if len(s.Enum) > 0 {
+		return s.Enum[0]
+	}
+
+	if len(s.AllOf) > 0 {
+		merged := map[string]any{}
+		for _, sub := range s.AllOf {
+			if m, ok := fromSchema(sub.Value, dir, depth+1).(map[string]any); ok {
+				for k, v := range m {
+					merged[k] = v
+				}
+			}
+		}
+		if obj, ok := objectOf(s, dir, depth); ok {
+			for k, v := range obj {
+				merged[k] = v
+			}
+		}
+		return merged
+	}
+	if len(s.OneOf) > 0 {
+		return fromSchema(s.OneOf[0].Value, dir, depth+1)
+	}
+	if len(s.AnyOf) > 0 {
+		return fromSchema(s.AnyOf[0].Value, dir, depth+1)
+	}
+
+	switch {
+	case s.Type.Includes(openapi3.TypeObject) || (s.Type == nil && len(s.Properties) > 0):
+		obj, _ := objectOf(s, dir, depth)
+		return obj
+	case s.Type.Includes(openapi3.TypeArray):
+		n := int(s.MinItems)
+		if n < 1 {
+			n = 1
+		}
+		var item *openapi3.Schema
+		if s.Items != nil {
+			item = s.Items.Value
+		}
+		out := make([]any, 0, n)
+		for i := 0; i < n; i++ {
+			out = append(out, fromSchema(item, dir, depth+1))
+		}
+		return out
+	case s.Type.Includes(openapi3.TypeString):
+		return String(s)
+	case s.Type.Includes(openapi3.TypeInteger):
+		return int64(Number(s))
+	case s.Type.Includes(openapi3.TypeNumber):
+		return Number(s)
+	case s.Type.Includes(openapi3.TypeBoolean):
+		return true
+	}
+	return nil
+}
+
+func objectOf(s *openapi3.Schema, dir Direction, depth int) (map[string]any, bool) {
+	if len(s.Properties) == 0 && !s.Type.Includes(openapi3.TypeObject) {
+		return nil, false
+	}
+	out := map[string]any{}
+	for name, ref := range s.Properties {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		p := ref.Value
+		if (dir == Request && p.ReadOnly) || (dir == Response && p.WriteOnly) {
+			continue
+		}
+		out[name] = fromSchema(p, dir, depth+1)
+	}
+	return out, true
+}
+
+// String returns a placeholder honouring format and length constraints.
+func String(s *openapi3.Schema) string {
+	var v string
+	switch s.Format {
+	case "date-time":
+		v = "2024-01-01T00:00:00Z"
+	case "date":
+		v = "2024-01-01"
+	case "time":
+		v = "00:00:00"
+	case "email":
+		v = "user@example.com"
+	case "uuid":
+		v = "00000000-0000-4000-8000-000000000000"
+	case "uri", "url":
+		v = "https://example.com"
+	case "hostname":
+		v = "example.com"
+	case "ipv4":
+		v = "192.0.2.1"
+	case "ipv6":
+		v = "2001:db8::1"
+	case "byte":
+		v = "c3RyaW5n"
+	default:
+		v = "string"
+	}
+	if n := int(s.MinLength); len(v) < n {
+		v += strings.Repeat("x", n-len(v))
+	}
+	if s.MaxLength != nil && uint64(len(v)) > *s.MaxLength {
+		v = v[:*s.MaxLength]
+	}
+	return v
+}
+
+// Number returns a value inside [minimum, maximum], respecting exclusivity
+// and multipleOf where it can.
+func Number(s *openapi3.Schema) float64 {
+	lo, hi := math.Inf(-1), math.Inf(1)
+	if s.Min != nil {
+		lo = *s.Min
+		if s.ExclusiveMin {
+			lo++
+		}
+	}
+	if s.Max != nil {
+		hi = *s.Max
+		if s.ExclusiveMax {
+			hi--
+		}
+	}
+	v := 1.0
+	switch {
+	case !math.IsInf(lo, -1) && v < lo:
+		v = lo
+	case !math.IsInf(hi, 1) && v > hi:
+		v = hi
+	}
+	if s.MultipleOf != nil && *s.MultipleOf > 0 {
+		m := *s.MultipleOf
+		v = math.Ceil(v/m) * m
+	}
+	return v
+}